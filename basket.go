@@ -0,0 +1,232 @@
+package monoacquiring
+
+import (
+	"math"
+	"slices"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+var ErrBasketTotalMismatch = errors.New("basket total does not match the amount")
+
+// Basket builds MerchantPaymentInfo.BasketOrder and MerchantPaymentInfo.Discounts that add up to the amount.
+//
+// The total of an item is qty×sum with its discounts applied, the total of the basket is the sum of the item
+// totals with the order discounts applied. Every fractional result is rounded to the minor unit half away
+// from zero: a PERCENT discount is a percentage of the total it applies to, a VALUE discount is in major
+// units, e.g. 12.5 is 12.50 UAH. The first error of a call is kept and returned by Items, Total and Apply.
+type Basket struct {
+	err       error
+	items     []BasketOrder
+	discounts []Discount
+	currency  Currency
+}
+
+// NewBasket returns an empty basket, the currency defines the minor unit of VALUE discounts.
+func NewBasket(currency Currency) *Basket {
+	return &Basket{currency: currency}
+}
+
+// Add adds an item of qty units of the sum price each.
+func (b *Basket) Add(name, code string, qty float64, sum int64) *Basket {
+	return b.AddItem(BasketOrder{Name: name, Code: code, Qty: qty, Sum: sum})
+}
+
+// AddItem adds the item as is, its Total is computed unless it is set, then it is checked.
+func (b *Basket) AddItem(item BasketOrder) *Basket {
+	if b.err != nil {
+		return b
+	}
+
+	if item.Qty <= 0 || item.Sum <= 0 {
+		b.err = errors.Errorf("basket item %q: qty and sum must be positive", item.Code)
+
+		return b
+	}
+
+	// clone the discounts, so Discount does not append to the slice of the caller
+	item.Discounts = slices.Clone(item.Discounts)
+
+	for _, discount := range item.Discounts {
+		if b.err = validateDiscount(discount); b.err != nil {
+			return b
+		}
+	}
+
+	b.items = append(b.items, item)
+
+	return b
+}
+
+// Discount decreases the total of the last added item.
+func (b *Basket) Discount(mode string, value float64) *Basket {
+	return b.itemDiscount(Discount{Type: DiscountTypeDiscount, Mode: mode, Value: value})
+}
+
+// ExtraCharge increases the total of the last added item.
+func (b *Basket) ExtraCharge(mode string, value float64) *Basket {
+	return b.itemDiscount(Discount{Type: DiscountTypeExtraCharge, Mode: mode, Value: value})
+}
+
+func (b *Basket) itemDiscount(discount Discount) *Basket {
+	if b.err != nil {
+		return b
+	}
+
+	if len(b.items) == 0 {
+		b.err = errors.New("basket discount without an item")
+
+		return b
+	}
+
+	if b.err = validateDiscount(discount); b.err != nil {
+		return b
+	}
+
+	last := &b.items[len(b.items)-1]
+	last.Discounts = append(last.Discounts, discount)
+
+	return b
+}
+
+// OrderDiscount applies the discount to the whole basket, it is sent in MerchantPaymentInfo.Discounts.
+func (b *Basket) OrderDiscount(discount Discount) *Basket {
+	if b.err != nil {
+		return b
+	}
+
+	if b.err = validateDiscount(discount); b.err == nil {
+		b.discounts = append(b.discounts, discount)
+	}
+
+	return b
+}
+
+func validateDiscount(discount Discount) error {
+	if discount.Type != DiscountTypeDiscount && discount.Type != DiscountTypeExtraCharge {
+		return errors.Errorf("unknown discount type %q", discount.Type)
+	}
+
+	if discount.Mode != DiscountModePercent && discount.Mode != DiscountModeValue {
+		return errors.Errorf("unknown discount mode %q", discount.Mode)
+	}
+
+	if discount.Value < 0.01 || math.IsInf(discount.Value, 0) || math.IsNaN(discount.Value) {
+		return errors.Errorf("discount value %v is out of range", discount.Value)
+	}
+
+	if discount.Type == DiscountTypeDiscount && discount.Mode == DiscountModePercent && discount.Value > 100 {
+		return errors.Errorf("discount of %v%% exceeds the total", discount.Value)
+	}
+
+	return nil
+}
+
+// Items returns the items with computed totals.
+func (b *Basket) Items() ([]BasketOrder, error) {
+	items, _, err := b.build()
+
+	return items, err
+}
+
+// Total returns the amount the basket adds up to.
+func (b *Basket) Total() (int64, error) {
+	_, total, err := b.build()
+
+	return total, err
+}
+
+func (b *Basket) build() ([]BasketOrder, int64, error) {
+	if b.err != nil {
+		return nil, 0, b.err
+	}
+
+	var (
+		items = make([]BasketOrder, len(b.items))
+		sum   int64
+	)
+
+	for i, item := range b.items {
+		total, err := b.apply(round(item.Qty*float64(item.Sum)), item.Discounts)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "basket item %q", item.Code)
+		}
+
+		if item.Total != nil && *item.Total != total {
+			return nil, 0, errors.Wrapf(ErrBasketTotalMismatch, "basket item %q: total %d, computed %d",
+				item.Code, *item.Total, total)
+		}
+
+		item.Total = util.Pointer(total)
+		items[i] = item
+		sum += total
+	}
+
+	total, err := b.apply(sum, b.discounts)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "basket")
+	}
+
+	return items, total, nil
+}
+
+// Apply sets the basket of the invoice. The amount is set to the total of the basket when it is zero,
+// otherwise a different total fails with ErrBasketTotalMismatch.
+func (b *Basket) Apply(payload *InvoiceCreateRequest) error {
+	if payload.Currency != nil && *payload.Currency != b.currency {
+		return errors.Wrapf(ErrCurrencyMismatch, "basket in %s, invoice in %s", b.currency, *payload.Currency)
+	}
+
+	items, total, err := b.build()
+	if err != nil {
+		return err
+	}
+
+	if payload.Amount == 0 {
+		payload.Amount = total
+	}
+
+	if payload.Amount != total {
+		return errors.Wrapf(ErrBasketTotalMismatch, "amount %d, basket total %d", payload.Amount, total)
+	}
+
+	info := MerchantPaymentInfo{}
+	if payload.MerchantPaymentInfo != nil {
+		info = *payload.MerchantPaymentInfo
+	}
+
+	info.BasketOrder = items
+	info.Discounts = b.discounts
+
+	payload.MerchantPaymentInfo = &info
+
+	return nil
+}
+
+// apply adds the discounts to the total, each of them is computed from the original total.
+func (b *Basket) apply(total int64, discounts []Discount) (int64, error) {
+	result := total
+
+	for _, discount := range discounts {
+		var value int64
+
+		if discount.Mode == DiscountModePercent {
+			value = round(float64(total) * discount.Value / 100)
+		} else {
+			value = round(discount.Value * math.Pow10(b.currency.Exponent()))
+		}
+
+		result += util.Ternary(discount.Type == DiscountTypeDiscount, -value, value)
+	}
+
+	if result < 0 {
+		return 0, errors.Errorf("discounts exceed the total %d", total)
+	}
+
+	return result, nil
+}
+
+func round(value float64) int64 {
+	return int64(math.Round(value))
+}
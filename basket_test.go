@@ -0,0 +1,70 @@
+package monoacquiring
+
+import (
+	"testing"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBasket() *Basket {
+	return NewBasket(CurrencyUAH).
+		Add("Хліб", "bread", 2, 2550).
+		Add("Сир", "cheese", 0.333, 19999).
+		Discount(DiscountModePercent, 10).
+		Add("Пакет", "bag", 1, 150).
+		ExtraCharge(DiscountModeValue, 0.5).
+		OrderDiscount(Discount{Type: DiscountTypeDiscount, Mode: DiscountModeValue, Value: 12.94})
+}
+
+func TestBasket(t *testing.T) {
+	items, err := testBasket().Items()
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, int64(5100), *items[0].Total)
+	assert.Equal(t, int64(5994), *items[1].Total, "6659.667 rounded to 6660 minus 10%")
+	assert.Equal(t, int64(200), *items[2].Total)
+	assert.Len(t, items[1].Discounts, 1)
+
+	total, err := testBasket().Total()
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000), total)
+}
+
+func TestBasket_Apply(t *testing.T) {
+	payload := InvoiceCreateRequest{MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer("order-1")}}
+
+	require.NoError(t, testBasket().Apply(&payload))
+	assert.Equal(t, int64(10000), payload.Amount)
+	assert.Equal(t, "order-1", *payload.MerchantPaymentInfo.Reference)
+	assert.Len(t, payload.MerchantPaymentInfo.BasketOrder, 3)
+	assert.Len(t, payload.MerchantPaymentInfo.Discounts, 1)
+
+	payload = InvoiceCreateRequest{Amount: 10001}
+	assert.ErrorIs(t, testBasket().Apply(&payload), ErrBasketTotalMismatch)
+	assert.Nil(t, payload.MerchantPaymentInfo)
+
+	payload = InvoiceCreateRequest{Amount: 10000, Currency: util.Pointer(CurrencyUSD)}
+	assert.ErrorIs(t, testBasket().Apply(&payload), ErrCurrencyMismatch)
+}
+
+func TestBasket_Error(t *testing.T) {
+	for name, val := range map[string]*Basket{
+		"no qty":           NewBasket(CurrencyUAH).Add("Хліб", "bread", 0, 2550),
+		"no item":          NewBasket(CurrencyUAH).Discount(DiscountModePercent, 10),
+		"unknown mode":     NewBasket(CurrencyUAH).Add("Хліб", "bread", 1, 2550).Discount("FIXED", 10),
+		"over 100 percent": NewBasket(CurrencyUAH).Add("Хліб", "bread", 1, 2550).Discount(DiscountModePercent, 101),
+		"below total":      NewBasket(CurrencyUAH).Add("Хліб", "bread", 1, 2550).Discount(DiscountModeValue, 25.51),
+		"unknown type": NewBasket(CurrencyUAH).Add("Хліб", "bread", 1, 2550).
+			OrderDiscount(Discount{Type: "MARKUP", Mode: DiscountModeValue, Value: 1}),
+		"item total": NewBasket(CurrencyUAH).
+			AddItem(BasketOrder{Name: "Хліб", Code: "bread", Qty: 2, Sum: 2550, Total: util.Pointer(int64(5000))}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := val.Total()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"runtime"
+	"slices"
+	"time"
 
 	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
 	"github.com/go-playground/validator/v10"
@@ -15,15 +18,29 @@ import (
 
 type (
 	Config struct {
-		APIKey     string `validate:"required"`
-		BaseURL    string `validate:"required,url"`
-		CMS        string
-		CMSVersion string
+		Retry *RetryPolicy `validate:"omitempty"`
+		// Idempotency records the attempts of payment-creating calls, see IdempotencyPolicy.
+		Idempotency *IdempotencyPolicy `validate:"omitempty"`
+		// RateLimit applies to every operation that has no limit of its own in RateLimits.
+		RateLimit *RateLimit `validate:"omitempty"`
+		// Logger enables LoggingMiddleware.
+		Logger *slog.Logger
+		// Observer is notified when an operation starts and ends, including all its retries.
+		Observer Observer
+		// RateLimits is keyed by the Operation* constants, e.g. OperationGetStatement.
+		RateLimits  map[string]RateLimit `validate:"omitempty,dive"`
+		APIKey      string               `validate:"required"`
+		BaseURL     string               `validate:"required,url"`
+		CMS         string
+		CMSVersion  string
+		Middlewares []Middleware
 	}
 
 	Client struct {
 		httpClient *http.Client
 		validator  *validator.Validate
+		limiter    *rateLimiter
+		transport  RoundTripper
 		cnf        Config
 	}
 )
@@ -81,7 +98,28 @@ func NewClient(config Config, httpClient *http.Client, validate *validator.Valid
 		return nil, err
 	}
 
-	return &Client{cnf: config, httpClient: httpClient, validator: validate}, nil
+	middlewares := slices.Clone(config.Middlewares)
+
+	if config.Logger != nil {
+		middlewares = append(middlewares, LoggingMiddleware(config.Logger))
+	}
+
+	limiter, err := newRateLimiter(config.RateLimit, config.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := RoundTripperFunc(func(_ string, req *http.Request) (*http.Response, error) {
+		return httpClient.Do(req)
+	})
+
+	return &Client{
+		cnf:        config,
+		httpClient: httpClient,
+		validator:  validate,
+		limiter:    limiter,
+		transport:  chainMiddlewares(transport, middlewares),
+	}, nil
 }
 
 func (c *Client) addHeaders(req *http.Request) *http.Request {
@@ -141,10 +179,51 @@ func (c *Client) newRequest(
 	return c.addHeaders(req), nil
 }
 
+// bodyDecoder is implemented by results that decode a successful response body themselves, e.g. as a stream.
+type bodyDecoder interface {
+	decodeBody(body io.Reader) error
+}
+
 func (c *Client) doReq(req *http.Request, result any) error {
-	res, err := c.httpClient.Do(req)
+	observer := c.cnf.Observer
+	if observer == nil {
+		_, _, err := c.exec(req, result)
+
+		return err
+	}
+
+	var (
+		operation = operationName(req)
+		start     = time.Now()
+	)
+
+	req = req.WithContext(observer.OperationStart(req.Context(), operation))
+
+	statusCode, attempts, err := c.exec(req, result)
+
+	opResult := OperationResult{
+		Err:        err,
+		Duration:   time.Since(start),
+		StatusCode: statusCode,
+		Retries:    max(attempts-1, 0),
+	}
+
+	var reqErr *RequestError
+
+	if errors.As(err, &reqErr) {
+		opResult.ErrCode = reqErr.Code
+	}
+
+	observer.OperationEnd(req.Context(), operation, opResult)
+
+	return err
+}
+
+// exec sends the request and decodes the response, it returns the last status code and the number of attempts.
+func (c *Client) exec(req *http.Request, result any) (int, int, error) {
+	res, attempts, err := c.send(req)
 	if err != nil {
-		return errors.WithStack(err)
+		return 0, attempts, err
 	}
 
 	defer func() {
@@ -161,17 +240,31 @@ func (c *Client) doReq(req *http.Request, result any) error {
 		}
 
 		if errCode, ok := statusToError[res.StatusCode]; ok {
-			return newRequestError(errCode, errorData.Code, errorData.Message)
+			return res.StatusCode, attempts, newRequestError(errCode, errorData.Code, errorData.Message)
 		}
 
-		return newRequestError(ErrUnexpectedHTTPStatus, errorData.Code, errorData.Message)
+		return res.StatusCode, attempts, newRequestError(ErrUnexpectedHTTPStatus, errorData.Code, errorData.Message)
+	}
+
+	if decoder, ok := result.(bodyDecoder); ok {
+		return res.StatusCode, attempts, decoder.decodeBody(res.Body)
 	}
 
 	if result != nil {
 		if err := json.NewDecoder(res.Body).Decode(result); err != nil {
-			return errors.WithStack(err)
+			return res.StatusCode, attempts, errors.WithStack(err)
 		}
 	}
 
-	return nil
+	return res.StatusCode, attempts, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if err := c.limiter.wait(req); err != nil {
+		return nil, err
+	}
+
+	res, err := c.transport.RoundTrip(operationName(req), req)
+
+	return res, errors.WithStack(err)
 }
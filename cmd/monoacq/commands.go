@@ -0,0 +1,510 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+)
+
+type command struct {
+	run   func(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error
+	name  string
+	usage string
+}
+
+// commands mirror the methods of the README table, grouped by the resource.
+var commands = []command{
+	{name: "invoice create", usage: "-amount 42.50 [-ccy UAH] [-hold] [-reference ref] [-destination text] " +
+		"[-redirect-url url] [-webhook-url url] [-validity 24h]", run: invoiceCreate},
+	{name: "invoice status", usage: "<invoice-id>", run: invoiceStatus},
+	{name: "invoice cancel", usage: "[-amount 42.50] <invoice-id>", run: invoiceCancel},
+	{name: "invoice remove", usage: "<invoice-id>", run: invoiceRemove},
+	{name: "qr list", usage: "", run: qrList},
+	{name: "qr details", usage: "<qr-id>", run: qrDetails},
+	{name: "qr reset", usage: "<qr-id>", run: qrReset},
+	{name: "statement", usage: "-from 2025-08-01|24h [-to 2025-08-02] [-code code]", run: statement},
+	{name: "wallet list", usage: "<wallet-id>", run: walletList},
+	{name: "wallet remove", usage: "<card-token>", run: walletRemove},
+	{name: "pubkey", usage: "", run: pubkey},
+	{name: "merchant", usage: "", run: merchant},
+	{name: "webhook verify", usage: "-sign <X-Sign> -body file.json [-pubkey key|-fetch]", run: webhookVerify},
+}
+
+// findCommand matches the longest command name at the start of args.
+func findCommand(args []string) (command, []string, bool) {
+	for words := min(len(args), 2); words > 0; words-- {
+		name := strings.Join(args[:words], " ")
+
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[words:], true
+			}
+		}
+	}
+
+	return command{}, nil, false
+}
+
+// parseArgs parses the flags, which may follow the arguments, and checks the number of arguments.
+func (a *app) parseArgs(fs *flag.FlagSet, args []string, names ...string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+
+			return nil, exitStatus(exitUsage)
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if err := checkOutput(a.output); err != nil {
+		return nil, err
+	}
+
+	if len(positional) != len(names) {
+		if len(names) == 0 {
+			return nil, usageError("unexpected arguments " + strings.Join(positional, " "))
+		}
+
+		return nil, usageError("expected " + strings.Join(names, " "))
+	}
+
+	return positional, nil
+}
+
+func invoiceCreate(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	var (
+		amount, ccy, reference, destination, redirectURL, webhookURL string
+		validity                                                     time.Duration
+		hold                                                         bool
+	)
+
+	fs.StringVar(&amount, "amount", "", "amount in major units, e.g. 42.50")
+	fs.StringVar(&ccy, "ccy", "UAH", "currency, alphabetic or numeric ISO 4217 code")
+	fs.BoolVar(&hold, "hold", false, "create a hold invoice")
+	fs.StringVar(&reference, "reference", "", "merchant reference")
+	fs.StringVar(&destination, "destination", "", "payment destination")
+	fs.StringVar(&redirectURL, "redirect-url", "", "URL the customer is redirected to after the payment")
+	fs.StringVar(&webhookURL, "webhook-url", "", "URL the status changes are sent to")
+	fs.DurationVar(&validity, "validity", 0, "validity of the invoice")
+
+	if _, err := a.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if amount == "" {
+		return usageError("-amount is required")
+	}
+
+	currency, err := monoacquiring.ParseCurrency(ccy)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	money, err := monoacquiring.ParseMoney(amount, currency)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	payload := monoacquiring.InvoiceCreateRequest{PaymentType: monoacquiring.PaymentTypeDebit}
+	payload.SetMoney(money)
+
+	if hold {
+		payload.PaymentType = monoacquiring.PaymentTypeHold
+	}
+
+	if reference != "" || destination != "" {
+		payload.MerchantPaymentInfo = &monoacquiring.MerchantPaymentInfo{
+			Reference:   optional(reference),
+			Destination: optional(destination),
+		}
+	}
+
+	payload.RedirectURL = optional(redirectURL)
+	payload.WebHookURL = optional(webhookURL)
+
+	if validity > 0 {
+		seconds := int64(validity.Seconds())
+		payload.Validity = &seconds
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.CreateInvoice(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	return a.print(result, fields("Invoice", result.InvoiceID, "Page URL", result.PageURL))
+}
+
+func invoiceStatus(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	args, err := a.parseArgs(fs, args, "<invoice-id>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: args[0]})
+	if err != nil {
+		return err
+	}
+
+	var final, maskedPan string
+
+	if money, ok := result.FinalMoney(); ok {
+		final = money.String()
+	}
+
+	if result.PaymentInfo != nil {
+		maskedPan = result.PaymentInfo.MaskedPan
+	}
+
+	info := fields(
+		"Invoice", result.InvoiceID,
+		"Status", result.Status.String(),
+		"Amount", result.Money().String(),
+		"Final amount", final,
+		"Refundable", refundable(result),
+		"Reference", valueOf(result.Reference),
+		"Destination", valueOf(result.Destination),
+		"Pay method", result.PayMethod.String(),
+		"Masked PAN", maskedPan,
+		"Error code", valueOf(result.ErrCode),
+		"Failure reason", valueOf(result.FailureReason),
+		"Created", result.CreatedDate.String(),
+		"Modified", result.ModifiedDate.String(),
+	)
+
+	if len(result.CancelList) == 0 {
+		return a.print(result, info)
+	}
+
+	cancels := table{header: []string{"CANCEL", "AMOUNT", "EXT REF", "CREATED", "MODIFIED"}}
+
+	for _, item := range result.CancelList {
+		cancels.add(item.Status.String(), item.Money().String(), item.ExternalReference,
+			item.CreatedDate.String(), item.ModifiedDate.String())
+	}
+
+	return a.print(result, info, cancels)
+}
+
+func refundable(status *monoacquiring.GetInvoiceStatusResponse) string {
+	if !status.Status.IsSuccess() && !status.Status.IsHold() {
+		return ""
+	}
+
+	return status.Refundable().String()
+}
+
+func invoiceCancel(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	var amount string
+
+	fs.StringVar(&amount, "amount", "", "amount to refund in major units, the whole refundable amount by default")
+
+	args, err := a.parseArgs(fs, args, "<invoice-id>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	var minor int64
+
+	if amount != "" {
+		status, err := client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: args[0]})
+		if err != nil {
+			return err
+		}
+
+		money, err := monoacquiring.ParseMoney(amount, status.Currency)
+		if err != nil {
+			return usageError(err.Error())
+		}
+
+		if money.Amount <= 0 {
+			return usageError("-amount must be positive")
+		}
+
+		minor = money.Amount
+	}
+
+	result, err := client.Refund(ctx, args[0], minor, nil)
+	if err != nil {
+		return err
+	}
+
+	return a.print(result, fields(
+		"Status", result.Status.String(),
+		"Amount", result.Money().String(),
+		"Ext ref", result.ExternalReference,
+		"Created", result.CreatedDate.String(),
+		"Modified", result.ModifiedDate.String(),
+	))
+}
+
+func invoiceRemove(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	args, err := a.parseArgs(fs, args, "<invoice-id>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	if err = client.RemoveInvoice(ctx, monoacquiring.RemoveInvoiceRequest{InvoiceID: args[0]}); err != nil {
+		return err
+	}
+
+	return a.printDone("invoice %s removed", args[0])
+}
+
+func qrList(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	if _, err := a.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetQRList(ctx)
+	if err != nil {
+		return err
+	}
+
+	list := table{header: []string{"QR", "SHORT QR", "AMOUNT TYPE", "PAGE URL"}}
+
+	for _, qr := range result.List {
+		list.add(qr.QrID, qr.ShortQrID, qr.AmountType.String(), qr.PageURL)
+	}
+
+	return a.print(result, list)
+}
+
+func qrDetails(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	args, err := a.parseArgs(fs, args, "<qr-id>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetQRDetails(ctx, monoacquiring.GetQrDetailsRequest{QrID: args[0]})
+	if err != nil {
+		return err
+	}
+
+	var amount string
+
+	if result.Amount != 0 {
+		amount = result.Money().String()
+	}
+
+	return a.print(result, fields("Short QR", result.ShortQrID, "Invoice", result.InvoiceID, "Amount", amount))
+}
+
+func qrReset(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	args, err := a.parseArgs(fs, args, "<qr-id>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	if err = client.QrResetAmount(ctx, monoacquiring.QrResetAmountRequest{QrID: args[0]}); err != nil {
+		return err
+	}
+
+	return a.printDone("amount of QR %s reset", args[0])
+}
+
+func statement(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	var from, to, code string
+
+	fs.StringVar(&from, "from", "", "start of the period, a date, an RFC 3339 time or a duration before now")
+	fs.StringVar(&to, "to", "", "end of the period, now by default")
+	fs.StringVar(&code, "code", "", "submerchant code")
+
+	if _, err := a.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if from == "" {
+		return usageError("-from is required")
+	}
+
+	now := time.Now()
+
+	payload := monoacquiring.GetStatementRequest{}
+
+	var err error
+
+	if payload.From, err = parseTime(from, now); err != nil {
+		return usageError("-from: " + err.Error())
+	}
+
+	if to != "" {
+		end, err := parseTime(to, now)
+		if err != nil {
+			return usageError("-to: " + err.Error())
+		}
+
+		payload.To = &end
+	}
+
+	payload.Code = optional(code)
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetStatement(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	list := table{header: []string{"DATE", "INVOICE", "STATUS", "AMOUNT", "MASKED PAN", "REFERENCE"}}
+
+	for _, item := range result.List {
+		list.add(item.Date.String(), item.InvoiceID, item.Status.String(), item.Money().String(), item.MaskedPan,
+			valueOf(item.Reference))
+	}
+
+	return a.print(result, list)
+}
+
+// parseTime accepts an RFC 3339 time, a local date or a duration before now.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, errors.Errorf("invalid time %q, expected 2006-01-02, 2006-01-02T15:04:05Z07:00 or 24h", value)
+}
+
+func walletList(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	args, err := a.parseArgs(fs, args, "<wallet-id>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetWalletCardList(ctx, monoacquiring.GetWalletCardListRequest{WalletID: args[0]})
+	if err != nil {
+		return err
+	}
+
+	list := table{header: []string{"CARD TOKEN", "MASKED PAN", "COUNTRY"}}
+
+	for _, card := range result.Wallet {
+		list.add(card.CardToken, card.MaskedPan, card.Country)
+	}
+
+	return a.print(result, list)
+}
+
+func walletRemove(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	args, err := a.parseArgs(fs, args, "<card-token>")
+	if err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	if err = client.RemoveWalletCard(ctx, monoacquiring.RemoveWalletCardRequest{CardToken: args[0]}); err != nil {
+		return err
+	}
+
+	return a.printDone("card %s removed", args[0])
+}
+
+func pubkey(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	if _, err := a.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetPublicKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	return a.print(result, table{rows: [][]string{{result.Key}}})
+}
+
+func merchant(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	if _, err := a.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	client, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetMerchantDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	return a.print(result, fields("Merchant", result.MerchantID, "Name", result.MerchantName, "EDRPOU", result.Edrpou))
+}
+
+// synopsis returns the name followed by the usage.
+func (c command) synopsis() string {
+	return strings.TrimSpace(c.name + " " + c.usage)
+}
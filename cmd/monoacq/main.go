@@ -0,0 +1,244 @@
+// Command monoacq calls the acquiring API from the command line.
+//
+//	monoacq [-config file] [-token token] [-base-url url] [-output table|json] <command> [flags] [args]
+//
+// The token is taken from -token, MONO_TOKEN or the config file, in that order. The config file is -config,
+// MONOACQ_CONFIG or $XDG_CONFIG_HOME/monoacq/config.json, a JSON object with "token" and optionally "baseUrl".
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	envToken   = "MONO_TOKEN"
+	envBaseURL = "MONO_BASE_URL"
+	envConfig  = "MONOACQ_CONFIG"
+
+	exitError = 1
+	exitUsage = 2
+)
+
+// exitStatus ends the command with the status once the command has reported the failure itself.
+type exitStatus int
+
+func (e exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// usageError is printed along with the usage of the command.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+type fileConfig struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"baseUrl"`
+}
+
+type app struct {
+	client     *monoacquiring.Client
+	stdout     io.Writer
+	stderr     io.Writer
+	env        func(string) string
+	configPath string
+	token      string
+	baseURL    string
+	output     string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, env func(string) string) int {
+	a := &app{stdout: stdout, stderr: stderr, env: env}
+
+	fs := flag.NewFlagSet("monoacq", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&a.configPath, "config", "", "config file")
+	fs.StringVar(&a.token, "token", "", "API token, overrides "+envToken)
+	fs.StringVar(&a.baseURL, "base-url", "", "API base URL, overrides "+envBaseURL)
+	fs.StringVar(&a.output, "output", outputTable, "output format, table or json")
+	fs.Usage = func() { a.usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return exitCode(err)
+	}
+
+	cmd, args, ok := findCommand(fs.Args())
+	if !ok {
+		a.usage(fs)
+
+		return exitUsage
+	}
+
+	err := cmd.run(ctx, a, a.flagSet(cmd), args)
+
+	var (
+		usageErr usageError
+		status   exitStatus
+	)
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &status):
+		return int(status)
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "monoacq %s: %v\nusage: monoacq %s\n", cmd.name, err, cmd.synopsis())
+
+		return exitUsage
+	}
+
+	fmt.Fprintf(stderr, "monoacq %s: %v\n", cmd.name, err)
+
+	return exitError
+}
+
+func exitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	return exitUsage
+}
+
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "usage: monoacq [flags] <command> [flags] [args]\n\ncommands:")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(a.stderr, "  %s\n", cmd.synopsis())
+	}
+
+	fmt.Fprintln(a.stderr, "\nflags:")
+	fs.PrintDefaults()
+}
+
+// flagSet returns the flags of the command, -output is accepted after the command too.
+func (a *app) flagSet(cmd command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.output, "output", a.output, "output format, table or json")
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: monoacq %s\n", cmd.synopsis())
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// apiClient creates the client on first use, so the commands working offline do not need a token.
+func (a *app) apiClient() (*monoacquiring.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	config, err := a.config()
+	if err != nil {
+		return nil, err
+	}
+
+	if a.client, err = monoacquiring.NewClient(config, nil, nil); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return a.client, nil
+}
+
+// config resolves the client configuration, the flags take precedence over the environment and the config file.
+func (a *app) config() (monoacquiring.Config, error) {
+	file, err := a.readConfig(a.configPath)
+	if err != nil {
+		return monoacquiring.Config{}, err
+	}
+
+	config := monoacquiring.Config{
+		APIKey:  firstNonEmpty(a.token, a.env(envToken), file.Token),
+		BaseURL: firstNonEmpty(a.baseURL, a.env(envBaseURL), file.BaseURL, monoacquiring.DefaultBaseURL),
+	}
+
+	if config.APIKey == "" {
+		return monoacquiring.Config{}, errors.Errorf("no token, set %s or the token of the config file", envToken)
+	}
+
+	return config, nil
+}
+
+func (a *app) readConfig(path string) (fileConfig, error) {
+	explicit := true
+
+	if path == "" {
+		path = a.env(envConfig)
+	}
+
+	if path == "" {
+		explicit = false
+		path = a.defaultConfigPath()
+	}
+
+	var config fileConfig
+
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // the path is chosen by the user
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+
+		return config, errors.WithStack(err)
+	}
+
+	if err = json.Unmarshal(data, &config); err != nil {
+		return config, errors.Wrapf(err, "failed to parse config file %s", path)
+	}
+
+	return config, nil
+}
+
+func (a *app) defaultConfigPath() string {
+	dir := a.env("XDG_CONFIG_HOME")
+
+	if dir == "" {
+		home := a.env("HOME")
+		if home == "" {
+			return ""
+		}
+
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "monoacq", "config.json")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
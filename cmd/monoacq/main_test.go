@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/monotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+var extRefPattern = regexp.MustCompile(`\b[0-9a-f]{32}\b`)
+
+type testServer struct {
+	*monotest.Server
+	url string
+}
+
+// newTestServer serves the QR, wallet and merchant endpoints monotest lacks and proxies the rest to monotest.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	srv, err := monotest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	srv.SetNow(func() time.Time { return time.Date(2025, 8, 11, 9, 30, 0, 0, time.UTC) })
+
+	target, err := url.Parse(srv.URL())
+	require.NoError(t, err)
+
+	reply := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", httputil.NewSingleHostReverseProxy(target))
+	mux.HandleFunc("GET /api/merchant/qr/list", reply(`{"list": [
+		{"shortQrId": "OBJE", "qrId": "XJ_DiM4rTd5V", "amountType": "merchant", "pageUrl": "https://pay.mbnk.biz/XJ_DiM4rTd5V"},
+		{"shortQrId": "OBJF", "qrId": "XJ_DiM4rTd5W", "amountType": "client", "pageUrl": "https://pay.mbnk.biz/XJ_DiM4rTd5W"}
+	]}`))
+	mux.HandleFunc("GET /api/merchant/qr/details", reply(
+		`{"shortQrId": "OBJE", "invoiceId": "250811monotest0099", "amount": 4200, "ccy": 980}`))
+	mux.HandleFunc("POST /api/merchant/qr/reset-amount", reply(`{}`))
+	mux.HandleFunc("GET /api/merchant/wallet", reply(`{"wallet": [
+		{"cardToken": "67XZtXdR4NpKU3", "maskedPan": "424242******4242", "country": "804"}
+	]}`))
+	mux.HandleFunc("DELETE /api/merchant/wallet/card", reply(`{}`))
+	mux.HandleFunc("GET /api/merchant/details", reply(
+		`{"merchantId": "12o4Vv7EWy", "merchantName": "Книжкова лавка", "edrpou": "4194800"}`))
+
+	proxy := httptest.NewServer(mux)
+	t.Cleanup(proxy.Close)
+
+	return &testServer{Server: srv, url: proxy.URL}
+}
+
+func (s *testServer) env(name string) string {
+	switch name {
+	case envToken:
+		return monotest.DefaultAPIKey
+	case envBaseURL:
+		return s.url
+	}
+
+	return ""
+}
+
+// runGolden runs the command and compares its output with testdata/<name>.golden.
+func runGolden(t *testing.T, srv *testServer, name string, code int, env func(string) string, args ...string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, code, run(context.Background(), args, &stdout, &stderr, env), stderr.String())
+
+	output := stdout.String()
+	if stderr.Len() > 0 {
+		output += "-- stderr --\n" + stderr.String()
+	}
+
+	output = strings.ReplaceAll(output, srv.url, "http://monotest")
+	output = strings.ReplaceAll(output, srv.URL(), "http://monotest")
+	output = strings.ReplaceAll(output, srv.PublicKey(), "<public-key>")
+	output = extRefPattern.ReplaceAllString(output, "<ext-ref>")
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(output), 0o600))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), output, name)
+}
+
+func TestRun_Invoice(t *testing.T) {
+	srv := newTestServer(t)
+	paid := "250811monotest0001"
+	removed := "250811monotest0002"
+
+	for _, step := range []struct {
+		before func() error
+		name   string
+		args   []string
+		code   int
+	}{
+		{name: "invoice_create", args: []string{"invoice", "create", "-amount", "42.50", "-reference", "order-1"}},
+		{name: "invoice_create_json", args: []string{"-output", "json", "invoice", "create", "-amount", "10"}},
+		{
+			name:   "invoice_status",
+			args:   []string{"invoice", "status", paid},
+			before: func() error { return srv.Pay(context.Background(), paid) },
+		},
+		{name: "invoice_cancel", args: []string{"invoice", "cancel", paid, "-amount", "12.50"}},
+		{name: "invoice_cancel_exceeds", args: []string{"invoice", "cancel", "-amount", "40", paid}, code: exitError},
+		{name: "invoice_status_cancelled", args: []string{"invoice", "status", paid}},
+		{name: "invoice_status_json", args: []string{"invoice", "status", paid, "-output", "json"}},
+		{name: "invoice_remove", args: []string{"invoice", "remove", removed}},
+		{name: "invoice_remove_twice", args: []string{"invoice", "remove", removed}, code: exitError},
+		{name: "statement", args: []string{"statement", "-from", "2025-08-11T00:00:00Z", "-to", "2025-08-12T00:00:00Z"}},
+	} {
+		if step.before != nil {
+			require.NoError(t, step.before())
+		}
+
+		runGolden(t, srv, step.name, step.code, srv.env, step.args...)
+	}
+}
+
+func TestRun_Commands(t *testing.T) {
+	srv := newTestServer(t)
+
+	for name, val := range map[string]struct {
+		args []string
+		code int
+	}{
+		"qr_list":            {args: []string{"qr", "list"}},
+		"qr_list_json":       {args: []string{"qr", "list", "-output", "json"}},
+		"qr_details":         {args: []string{"qr", "details", "XJ_DiM4rTd5V"}},
+		"qr_reset":           {args: []string{"qr", "reset", "XJ_DiM4rTd5V"}},
+		"wallet_list":        {args: []string{"wallet", "list", "c1376a611e17b059aeaf96b73258da9c"}},
+		"wallet_remove_json": {args: []string{"-output", "json", "wallet", "remove", "67XZtXdR4NpKU3"}},
+		"pubkey":             {args: []string{"pubkey"}},
+		"merchant":           {args: []string{"merchant"}},
+		"usage":              {args: []string{"invoice"}, code: exitUsage},
+		"missing_argument":   {args: []string{"invoice", "status"}, code: exitUsage},
+		"missing_amount":     {args: []string{"invoice", "create", "-ccy", "USD"}, code: exitUsage},
+		"unknown_output":     {args: []string{"-output", "yaml", "merchant"}, code: exitUsage},
+		"unknown_invoice":    {args: []string{"invoice", "status", "unknown"}, code: exitError},
+	} {
+		runGolden(t, srv, name, val.code, srv.env, val.args...)
+	}
+}
+
+func TestRun_Config(t *testing.T) {
+	srv := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"token": "`+monotest.DefaultAPIKey+`", "baseUrl": "`+srv.url+`"}`), 0o600))
+
+	noEnv := func(string) string { return "" }
+
+	runGolden(t, srv, "merchant", 0, noEnv, "-config", path, "merchant")
+	runGolden(t, srv, "merchant", 0, func(name string) string {
+		return map[string]string{envConfig: path, envToken: "ignored-by-the-flag"}[name]
+	}, "-token", monotest.DefaultAPIKey, "merchant")
+	runGolden(t, srv, "missing_token", exitError, noEnv, "merchant")
+}
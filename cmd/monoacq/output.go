@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// table is the human-readable form of a result. A table without a header is printed as label-value pairs.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// fields returns a label-value table of the pairs, skipping the empty values.
+func fields(pairs ...string) table {
+	var t table
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			t.rows = append(t.rows, []string{pairs[i] + ":", pairs[i+1]})
+		}
+	}
+
+	return t
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+// print writes the value as JSON or the tables separated by empty lines, depending on -output.
+func (a *app) print(value any, tables ...table) error {
+	if a.output == outputJSON {
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+
+		return errors.WithStack(encoder.Encode(value))
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		if t.header != nil {
+			fmt.Fprintln(w, strings.Join(t.header, "\t"))
+		}
+
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+	}
+
+	return errors.WithStack(w.Flush())
+}
+
+// printDone reports a call without a result, the message in a table and an empty object in JSON.
+func (a *app) printDone(format string, args ...any) error {
+	if a.output == outputJSON {
+		return a.print(struct{}{})
+	}
+
+	_, err := fmt.Fprintf(a.stdout, format+"\n", args...)
+
+	return errors.WithStack(err)
+}
+
+func checkOutput(output string) error {
+	if output != outputTable && output != outputJSON {
+		return usageError(fmt.Sprintf("unknown output format %q, expected table or json", output))
+	}
+
+	return nil
+}
+
+func valueOf[T any](value *T) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(*value)
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
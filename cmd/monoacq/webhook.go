@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"os"
+	"strings"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/webhook"
+	"github.com/pkg/errors"
+)
+
+type verifyResult struct {
+	Problems []string `json:"problems,omitempty"`
+	Valid    bool     `json:"valid"`
+}
+
+func (r *verifyResult) problem(text string) {
+	r.Problems = append(r.Problems, text)
+}
+
+// webhookVerify checks the X-Sign of a saved webhook body and explains why it does not match.
+func webhookVerify(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	var (
+		sign, bodyPath, pubKey string
+		fetch                  bool
+	)
+
+	fs.StringVar(&sign, "sign", "", "value of the X-Sign header")
+	fs.StringVar(&bodyPath, "body", "", "file with the raw request body")
+	fs.StringVar(&pubKey, "pubkey", "", "public key as returned by the pubkey command")
+	fs.BoolVar(&fetch, "fetch", false, "fetch the public key from the API, the default without -pubkey")
+
+	if _, err := a.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	switch {
+	case sign == "" || bodyPath == "":
+		return usageError("-sign and -body are required")
+	case pubKey != "" && fetch:
+		return usageError("-pubkey and -fetch are exclusive")
+	}
+
+	body, err := os.ReadFile(bodyPath) //nolint:gosec // the path is chosen by the user
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	fetched := pubKey == ""
+
+	if fetched {
+		if pubKey, err = a.fetchPublicKey(ctx); err != nil {
+			return err
+		}
+	}
+
+	result := verifySignature(strings.TrimSpace(sign), body, strings.TrimSpace(pubKey), fetched)
+
+	if err = a.print(result, verifyTable(result)); err != nil {
+		return err
+	}
+
+	if !result.Valid {
+		return exitStatus(exitError)
+	}
+
+	return nil
+}
+
+func (a *app) fetchPublicKey(ctx context.Context) (string, error) {
+	client, err := a.apiClient()
+	if err != nil {
+		return "", err
+	}
+
+	result, err := client.GetPublicKey(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch the public key")
+	}
+
+	return result.Key, nil
+}
+
+func verifyTable(result verifyResult) table {
+	t := fields("Signature", map[bool]string{true: "valid", false: "not valid"}[result.Valid])
+
+	for _, problem := range result.Problems {
+		t.add("Problem:", problem)
+	}
+
+	return t
+}
+
+func verifySignature(sign string, body []byte, pubKey string, fetched bool) verifyResult {
+	var result verifyResult
+
+	signOK := checkSignature(sign, &result)
+
+	verifier, err := webhook.NewSignatureVerifier(pubKey)
+	if err != nil {
+		result.problem(explainKey(pubKey, err))
+	}
+
+	if !signOK || verifier == nil {
+		return result
+	}
+
+	if result.Valid, _ = verifier.Verify(sign, body); result.Valid {
+		return result
+	}
+
+	for _, variant := range bodyVariants(body) {
+		if ok, _ := verifier.Verify(sign, variant.body); ok {
+			result.problem("the signature matches the body " + variant.change + ", the webhook body must be " +
+				"saved and verified byte for byte as received")
+
+			return result
+		}
+	}
+
+	if json.Valid(body) {
+		result.problem("the signature does not match the body: it must be the raw request body, a body decoded " +
+			"and encoded again (reordered keys, changed spacing or escaping, e.g. by a logger or a framework) " +
+			"does not verify")
+	} else {
+		result.problem("the signature does not match the body, which is not valid JSON")
+	}
+
+	if fetched {
+		result.problem("the key is the current one of the API, the webhook may have been sent to another merchant")
+	} else {
+		result.problem("the key may be outdated after a rotation, try -fetch")
+	}
+
+	return result
+}
+
+// checkSignature explains the signatures that can not match any body.
+func checkSignature(sign string, result *verifyResult) bool {
+	decoded, err := base64.StdEncoding.DecodeString(sign)
+	if err != nil {
+		switch {
+		case decodes(base64.StdEncoding, strings.ReplaceAll(sign, " ", "+")):
+			result.problem("the signature contains spaces, a '+' of the X-Sign header was probably URL-decoded " +
+				"into a space: " + err.Error())
+		case decodes(base64.URLEncoding, sign) || decodes(base64.RawURLEncoding, sign):
+			result.problem("the signature is base64url encoded, X-Sign is standard base64 with '+' and '/': " +
+				err.Error())
+		default:
+			result.problem("the signature is not base64, pass only the value of the X-Sign header: " + err.Error())
+		}
+
+		return false
+	}
+
+	var signature struct {
+		R, S *big.Int
+	}
+
+	if rest, err := asn1.Unmarshal(decoded, &signature); err != nil || len(rest) > 0 {
+		result.problem("the signature is base64 but not an ECDSA signature, is it the X-Sign header of the webhook?")
+
+		return false
+	}
+
+	return true
+}
+
+func decodes(encoding *base64.Encoding, value string) bool {
+	_, err := encoding.DecodeString(value)
+
+	return err == nil
+}
+
+// explainKey turns the errors of webhook.NewSignatureVerifier into advice.
+func explainKey(pubKey string, err error) string {
+	switch {
+	case pubKey == "":
+		return "the public key is empty"
+	case strings.HasPrefix(pubKey, "-----BEGIN"):
+		return "the public key is PEM, pass it base64 encoded as returned by the pubkey command"
+	case strings.Contains(err.Error(), "failed to assert type of public key"):
+		return "the public key is not an ECDSA key (" + err.Error() + "), webhooks are signed with the ECDSA " +
+			"key returned by the pubkey command, not with the key of a TLS certificate or another service"
+	case strings.Contains(err.Error(), "failed to decode PEM block"):
+		return "the public key is base64 but not of a PEM block, pass it as returned by the pubkey command"
+	}
+
+	if _, decodeErr := base64.StdEncoding.DecodeString(pubKey); decodeErr != nil {
+		return "the public key is not base64, pass it as returned by the pubkey command: " + err.Error()
+	}
+
+	return "the public key can not be parsed: " + err.Error()
+}
+
+type bodyVariant struct {
+	change string
+	body   []byte
+}
+
+// bodyVariants undoes the usual changes of a saved body, the trailing newline of an editor and pretty printing.
+func bodyVariants(body []byte) []bodyVariant {
+	var variants []bodyVariant
+
+	if trimmed := bytes.TrimRight(body, "\r\n"); len(trimmed) != len(body) {
+		variants = append(variants, bodyVariant{change: "without the trailing newline", body: trimmed})
+	}
+
+	compact := new(bytes.Buffer)
+	if json.Compact(compact, body) == nil && !bytes.Equal(compact.Bytes(), bytes.TrimSpace(body)) {
+		variants = append(variants, bodyVariant{change: "with the spacing removed", body: compact.Bytes()})
+	}
+
+	return variants
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookBody = `{"invoiceId":"250811monotest0001","status":"success","amount":4250,"ccy":980,` +
+	`"reference":"order-1","destination":"Оплата замовлення","modifiedDate":"2025-08-11T09:30:00Z"}`
+
+func testPublicKey(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestRun_WebhookVerify(t *testing.T) {
+	srv := newTestServer(t)
+	dir := t.TempDir()
+
+	sign, err := srv.Sign([]byte(testWebhookBody))
+	require.NoError(t, err)
+
+	indented := new(bytes.Buffer)
+	require.NoError(t, json.Indent(indented, []byte(testWebhookBody), "", "  "))
+
+	var event map[string]any
+
+	require.NoError(t, json.Unmarshal([]byte(testWebhookBody), &event))
+
+	reserialized, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	bodies := map[string][]byte{
+		"raw":          []byte(testWebhookBody),
+		"newline":      []byte(testWebhookBody + "\n"),
+		"indented":     indented.Bytes(),
+		"reserialized": reserialized,
+	}
+
+	for name, body := range bodies {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), body, 0o600))
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemKey, err := base64.StdEncoding.DecodeString(srv.PublicKey())
+	require.NoError(t, err)
+
+	verifySign := func(sign, body string, args ...string) []string {
+		return append([]string{"webhook", "verify", "-sign", sign, "-body", filepath.Join(dir, body+".json")}, args...)
+	}
+
+	verify := func(body string, args ...string) []string {
+		return verifySign(sign, body, args...)
+	}
+
+	rsaPublicKey := testPublicKey(t, &rsaKey.PublicKey)
+	otherPublicKey := testPublicKey(t, &otherKey.PublicKey)
+
+	for name, val := range map[string]struct {
+		args []string
+		code int
+	}{
+		"webhook_verify":              {args: verify("raw", "-pubkey", srv.PublicKey())},
+		"webhook_verify_fetch":        {args: verify("raw", "-fetch", "-output", "json")},
+		"webhook_verify_newline":      {args: verify("newline"), code: exitError},
+		"webhook_verify_indented":     {args: verify("indented"), code: exitError},
+		"webhook_verify_reserialized": {args: verify("reserialized", "-pubkey", srv.PublicKey()), code: exitError},
+		"webhook_verify_other_key":    {args: verify("raw", "-pubkey", otherPublicKey), code: exitError},
+		"webhook_verify_rsa_key":      {args: verify("raw", "-pubkey", rsaPublicKey), code: exitError},
+		"webhook_verify_pem_key":      {args: verify("raw", "-pubkey", string(pemKey)), code: exitError},
+		"webhook_verify_not_base64":   {args: verifySign("X-Sign: MEQCIEaJ", "raw"), code: exitError},
+		"webhook_verify_url_decoded": {
+			args: verifySign("MEQCIEaJMN/d0xcZoEgI1zya yE6GYJb2f2osBZMPgjtXNUiAiAGVfUR9dxj2Ix7blF7MjMdAU2VZcpuyUuB6z"+
+				"ncVoFadg==", "raw"),
+			code: exitError,
+		},
+		"webhook_verify_base64url": {
+			args: verifySign("MEQCIEaJMN_d0xcZoEgI1zya-yE6GYJb2f2osBZMPgjtXNUi", "raw"),
+			code: exitError,
+		},
+		"webhook_verify_not_signature": {args: verifySign("c29tZSByYW5kb20gdGV4dA==", "raw"), code: exitError},
+		"webhook_verify_exclusive":     {args: verify("raw", "-pubkey", srv.PublicKey(), "-fetch"), code: exitUsage},
+	} {
+		runGolden(t, srv, name, val.code, srv.env, val.args...)
+	}
+}
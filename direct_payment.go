@@ -16,7 +16,7 @@ import (
 type DirectPaymentRequest struct {
 	MerchantPaymentInfo *MerchantPaymentInfo `json:"merchantPaymInfo,omitempty" validate:"omitempty"`
 	SaveCardData        *SaveCardData        `json:"saveCardData,omitempty" validate:"omitempty"`
-	Currency            *int                 `json:"ccy" validate:"omitempty,iso4217_numeric"`
+	Currency            *Currency            `json:"ccy" validate:"omitempty,iso4217_numeric"`
 	InitiationKind      *string              `json:"initiationKind" validate:"omitempty,oneof=merchant client"`
 	Card                DirectPaymentCard    `json:"cardData" validate:"required"`
 	PaymentType         string               `json:"paymentType" validate:"required,oneof=debit hold"`
@@ -24,14 +24,14 @@ type DirectPaymentRequest struct {
 }
 
 type DirectPaymentResponse struct {
-	InvoiceID     string              `json:"invoiceId"`
-	TdsURL        string              `json:"tdsUrl"`
-	Status        DirectPaymentStatus `json:"status"`
-	FailureReason string              `json:"failureReason"`
-	CreatedDate   string              `json:"createdDate"`
-	ModifiedDate  string              `json:"modifiedDate"`
-	Amount        int                 `json:"amount"`
-	Ccy           int                 `json:"ccy"`
+	InvoiceID     string        `json:"invoiceId"`
+	TdsURL        string        `json:"tdsUrl"`
+	Status        InvoiceStatus `json:"status"`
+	FailureReason string        `json:"failureReason"`
+	CreatedDate   Timestamp     `json:"createdDate"`
+	ModifiedDate  Timestamp     `json:"modifiedDate"`
+	Amount        int64         `json:"amount"`
+	Currency      Currency      `json:"ccy"`
 }
 
 func (c *Client) DirectPayment(ctx context.Context, payload DirectPaymentRequest) (*DirectPaymentResponse, error) {
@@ -44,6 +44,11 @@ func (c *Client) DirectPayment(ctx context.Context, payload DirectPaymentRequest
 		return nil, errors.WithStack(err)
 	}
 
+	key, err := c.beginAttempt(ctx, OperationDirectPayment, payload.MerchantPaymentInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := new(bytes.Buffer)
 	if err = json.NewEncoder(buf).Encode(payload); err != nil {
 		return nil, errors.Wrap(err, "failed to marshal direct payment request")
@@ -56,7 +61,14 @@ func (c *Client) DirectPayment(ctx context.Context, payload DirectPaymentRequest
 
 	var result DirectPaymentResponse
 
-	if err = c.doReq(req, &result); err != nil {
+	err = c.doReq(req, &result)
+	err = c.endAttempt(ctx, key, result.InvoiceID, err)
+
+	if errors.Is(err, ErrPaymentNotRecorded) {
+		return &result, err
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
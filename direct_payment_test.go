@@ -54,7 +54,7 @@ func TestDirectPayment(t *testing.T) {
 			CVV:        "123",
 		},
 		Amount:         1000,
-		Currency:       util.Pointer(980),
+		Currency:       util.Pointer(CurrencyUAH),
 		InitiationKind: util.Pointer(InitiationKindMerchant),
 	}
 
@@ -174,7 +174,7 @@ func TestDirectPayment_NetworkError(t *testing.T) {
 					CVV:        "123",
 				},
 				Amount:         1000,
-				Currency:       util.Pointer(980),
+				Currency:       util.Pointer(CurrencyUAH),
 				InitiationKind: util.Pointer(InitiationKindMerchant),
 			}
 			res, err := client.DirectPayment(ctx, req)
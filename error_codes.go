@@ -0,0 +1,177 @@
+package monoacquiring
+
+import (
+	"context"
+	"net"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+// Codes returned in errCode of an unsuccessful API response.
+const (
+	ErrCodeBadRequest       = "BAD_REQUEST"
+	ErrCodeForbidden        = "FORBIDDEN"
+	ErrCodeNotFound         = "NOT_FOUND"
+	ErrCodeTooManyRequests  = "TOO_MANY_REQUESTS"
+	ErrCodeInternal         = "INTERNAL_ERROR"
+	ErrCodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+)
+
+// Codes returned in errCode of a failed payment together with failureReason, the failureReason text is for
+// humans and may change, branch on these codes instead.
+const (
+	ErrCodeCardBlocked             = "6"
+	ErrCodeCardLost                = "40"
+	ErrCodeCardStolen              = "41"
+	ErrCodeCardNotPermitted        = "50"
+	ErrCodeCardExpired             = "51"
+	ErrCodeInvalidCardNumber       = "52"
+	ErrCodeTechnicalFailure        = "54"
+	ErrCodeCardNotSupported        = "56"
+	ErrCodeTransactionNotPermitted = "57"
+	ErrCodeOnlineLimitExceeded     = "58"
+	ErrCodeInvalidCVV              = "59"
+	ErrCodeInsufficientFunds       = "60"
+	ErrCodeAmountLimitExceeded     = "61"
+	ErrCodeCountLimitExceeded      = "62"
+	ErrCodeSuspectedFraud          = "71"
+	ErrCode3DSFailed               = "74"
+	ErrCodeTimeout                 = "98"
+)
+
+type ErrCode string
+
+func (ec ErrCode) String() string {
+	return string(ec)
+}
+
+func (ec ErrCode) IsBadRequest() bool {
+	return ec.String() == ErrCodeBadRequest
+}
+
+func (ec ErrCode) IsForbidden() bool {
+	return ec.String() == ErrCodeForbidden
+}
+
+func (ec ErrCode) IsNotFound() bool {
+	return ec.String() == ErrCodeNotFound
+}
+
+func (ec ErrCode) IsTooManyRequests() bool {
+	return ec.String() == ErrCodeTooManyRequests
+}
+
+func (ec ErrCode) IsInternal() bool {
+	return ec.String() == ErrCodeInternal
+}
+
+func (ec ErrCode) IsMethodNotAllowed() bool {
+	return ec.String() == ErrCodeMethodNotAllowed
+}
+
+func (ec ErrCode) IsInvalidCVV() bool {
+	return ec.String() == ErrCodeInvalidCVV
+}
+
+func (ec ErrCode) IsInsufficientFunds() bool {
+	return ec.String() == ErrCodeInsufficientFunds
+}
+
+func (ec ErrCode) IsCardExpired() bool {
+	return ec.String() == ErrCodeCardExpired
+}
+
+// IsCardUnusable reports whether the card can not be charged again: blocked, lost, stolen or expired.
+func (ec ErrCode) IsCardUnusable() bool {
+	switch ec.String() {
+	case ErrCodeCardBlocked, ErrCodeCardLost, ErrCodeCardStolen, ErrCodeCardExpired:
+		return true
+	}
+
+	return false
+}
+
+// IsLimitExceeded reports whether a limit of the card was hit, the payment may succeed later or with another amount.
+func (ec ErrCode) IsLimitExceeded() bool {
+	switch ec.String() {
+	case ErrCodeOnlineLimitExceeded, ErrCodeAmountLimitExceeded, ErrCodeCountLimitExceeded:
+		return true
+	}
+
+	return false
+}
+
+// IsRetryable reports whether the same request may succeed when sent again later, for an API error or a payment
+// failed for a technical reason.
+func (ec ErrCode) IsRetryable() bool {
+	switch ec.String() {
+	case ErrCodeTooManyRequests, ErrCodeInternal, ErrCodeTechnicalFailure, ErrCodeTimeout:
+		return true
+	}
+
+	return false
+}
+
+func (e *RequestError) ErrCode() ErrCode {
+	return ErrCode(e.Code)
+}
+
+func (r *GetInvoiceStatusResponse) FailureCode() ErrCode {
+	return ErrCode(util.PointerValue(r.ErrCode))
+}
+
+func (r *GetInvoiceStatusResponse) IsInsufficientFunds() bool {
+	return r.FailureCode().IsInsufficientFunds()
+}
+
+func (r *SyncPaymentResponse) FailureCode() ErrCode {
+	return ErrCode(util.PointerValue(r.ErrCode))
+}
+
+func (r *SyncPaymentResponse) IsInsufficientFunds() bool {
+	return r.FailureCode().IsInsufficientFunds()
+}
+
+// IsInvoiceNotFound reports whether the API did not find the requested invoice. A 404 without the NOT_FOUND
+// errCode, e.g. of a wrong path, is not reported.
+func IsInvoiceNotFound(err error) bool {
+	var reqErr *RequestError
+
+	if !errors.As(err, &reqErr) {
+		return false
+	}
+
+	return reqErr.ErrCode().IsNotFound()
+}
+
+// IsInsufficientFunds reports whether the API rejected a payment because of insufficient funds.
+func IsInsufficientFunds(err error) bool {
+	var reqErr *RequestError
+
+	if !errors.As(err, &reqErr) {
+		return false
+	}
+
+	return reqErr.ErrCode().IsInsufficientFunds()
+}
+
+// IsRetryable reports whether the same request may succeed when sent again later:
+// rate limiting, internal server errors and network failures.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var reqErr *RequestError
+
+	if errors.As(err, &reqErr) {
+		return errors.Is(reqErr, ErrTooManyRequestsHTTPStatus) ||
+			errors.Is(reqErr, ErrInternalHTTPStatus) ||
+			reqErr.ErrCode().IsRetryable()
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,172 @@
+package monoacquiring
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrCode(t *testing.T) {
+	nf := ErrCode("NOT_FOUND")
+	assert.Equal(t, "NOT_FOUND", nf.String())
+	assert.True(t, nf.IsNotFound())
+	assert.False(t, nf.IsBadRequest())
+	assert.False(t, nf.IsRetryable())
+
+	br := ErrCode("BAD_REQUEST")
+	assert.True(t, br.IsBadRequest())
+	assert.False(t, br.IsForbidden())
+
+	f := ErrCode("FORBIDDEN")
+	assert.True(t, f.IsForbidden())
+	assert.False(t, f.IsNotFound())
+
+	mna := ErrCode("METHOD_NOT_ALLOWED")
+	assert.True(t, mna.IsMethodNotAllowed())
+	assert.False(t, mna.IsRetryable())
+
+	tmr := ErrCode("TOO_MANY_REQUESTS")
+	assert.True(t, tmr.IsTooManyRequests())
+	assert.True(t, tmr.IsRetryable())
+
+	i := ErrCode("INTERNAL_ERROR")
+	assert.True(t, i.IsInternal())
+	assert.True(t, i.IsRetryable())
+
+	cvv := ErrCode("59")
+	assert.True(t, cvv.IsInvalidCVV())
+	assert.False(t, cvv.IsRetryable())
+	assert.False(t, cvv.IsCardUnusable())
+
+	for code, expected := range map[string]struct {
+		insufficientFunds, unusable, limit, retryable bool
+	}{
+		ErrCodeInsufficientFunds:   {insufficientFunds: true},
+		ErrCodeCardExpired:         {unusable: true},
+		ErrCodeCardStolen:          {unusable: true},
+		ErrCodeAmountLimitExceeded: {limit: true},
+		ErrCodeCountLimitExceeded:  {limit: true},
+		ErrCodeTechnicalFailure:    {retryable: true},
+		ErrCodeTimeout:             {retryable: true},
+		ErrCodeSuspectedFraud:      {},
+	} {
+		ec := ErrCode(code)
+
+		assert.Equal(t, expected.insufficientFunds, ec.IsInsufficientFunds(), code)
+		assert.Equal(t, expected.unusable, ec.IsCardUnusable(), code)
+		assert.Equal(t, expected.limit, ec.IsLimitExceeded(), code)
+		assert.Equal(t, expected.retryable, ec.IsRetryable(), code)
+	}
+}
+
+func TestIsInvoiceNotFound(t *testing.T) {
+	tests := map[string]struct {
+		Err      error
+		Expected bool
+	}{
+		"not found": {
+			Err:      newRequestError(ErrNotFoundHTTPStatus, ErrCodeNotFound, "invoice not found"),
+			Expected: true,
+		},
+		"wrong path": {
+			Err:      newRequestError(ErrNotFoundHTTPStatus, "", "404 page not found"),
+			Expected: false,
+		},
+		"not found code": {
+			Err:      errors.WithStack(newRequestError(ErrBadRequestHTTPStatus, ErrCodeNotFound, "")),
+			Expected: true,
+		},
+		"bad request": {
+			Err:      newRequestError(ErrBadRequestHTTPStatus, ErrCodeBadRequest, "empty 'invoiceId'"),
+			Expected: false,
+		},
+		"other error": {
+			Err:      errors.New("test"),
+			Expected: false,
+		},
+		"nil": {
+			Err:      nil,
+			Expected: false,
+		},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, val.Expected, IsInvoiceNotFound(val.Err))
+		})
+	}
+}
+
+func TestIsInsufficientFunds(t *testing.T) {
+	assert.True(t, IsInsufficientFunds(newRequestError(ErrBadRequestHTTPStatus, ErrCodeInsufficientFunds, "")))
+	assert.False(t, IsInsufficientFunds(newRequestError(ErrBadRequestHTTPStatus, "", "Недостатньо коштів на картці")),
+		"the message text is not matched")
+	assert.False(t, IsInsufficientFunds(errors.New("недостатньо коштів")))
+
+	status := GetInvoiceStatusResponse{
+		ErrCode:       util.Pointer("59"),
+		FailureReason: util.Pointer("Неправильний CVV код"),
+	}
+
+	assert.True(t, status.FailureCode().IsInvalidCVV())
+	assert.False(t, status.IsInsufficientFunds())
+
+	sync := SyncPaymentResponse{ErrCode: util.Pointer(ErrCodeInsufficientFunds), FailureReason: util.Pointer("Відмова")}
+
+	assert.True(t, sync.IsInsufficientFunds())
+	assert.False(t, (&SyncPaymentResponse{FailureReason: util.Pointer("Недостатньо коштів")}).IsInsufficientFunds())
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := map[string]struct {
+		Err      error
+		Expected bool
+	}{
+		"too many requests": {
+			Err:      newRequestError(ErrTooManyRequestsHTTPStatus, ErrCodeTooManyRequests, ""),
+			Expected: true,
+		},
+		"internal": {
+			Err:      newRequestError(ErrInternalHTTPStatus, "", ""),
+			Expected: true,
+		},
+		"payment timeout": {
+			Err:      newRequestError(ErrBadRequestHTTPStatus, ErrCodeTimeout, ""),
+			Expected: true,
+		},
+		"internal code": {
+			Err:      newRequestError(ErrUnexpectedHTTPStatus, ErrCodeInternal, ""),
+			Expected: true,
+		},
+		"bad request": {
+			Err:      newRequestError(ErrBadRequestHTTPStatus, ErrCodeBadRequest, ""),
+			Expected: false,
+		},
+		"network": {
+			Err:      errors.WithStack(&net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+			Expected: true,
+		},
+		"canceled": {
+			Err:      errors.WithStack(context.Canceled),
+			Expected: false,
+		},
+		"other": {
+			Err:      errors.New("test"),
+			Expected: false,
+		},
+		"nil": {
+			Err:      nil,
+			Expected: false,
+		},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, val.Expected, IsRetryable(val.Err))
+		})
+	}
+}
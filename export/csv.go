@@ -0,0 +1,273 @@
+// Package export writes statements returned by GetStatement to files for spreadsheet tools.
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+// Column is a column of the export, its value is the default header.
+type Column string
+
+const (
+	// ColumnRowType is RowTypePayment for a statement and RowTypeCancel for an item of its cancel list.
+	ColumnRowType       Column = "type"
+	ColumnInvoiceID     Column = "invoiceId"
+	ColumnDate          Column = "date"
+	ColumnStatus        Column = "status"
+	ColumnAmount        Column = "amount"
+	ColumnCurrency      Column = "ccy"
+	ColumnProfitAmount  Column = "profitAmount"
+	ColumnPaymentScheme Column = "paymentScheme"
+	ColumnMaskedPan     Column = "maskedPan"
+	ColumnApprovalCode  Column = "approvalCode"
+	ColumnRRN           Column = "rrn"
+	ColumnReference     Column = "reference"
+	ColumnShortQrID     Column = "shortQrId"
+	ColumnDestination   Column = "destination"
+
+	RowTypePayment = "payment"
+	RowTypeCancel  = "cancel"
+
+	DefaultDateLayout = "2006-01-02 15:04:05"
+
+	bom = "\ufeff"
+)
+
+// DefaultColumns are all columns in the order of the statement model.
+var DefaultColumns = []Column{
+	ColumnRowType,
+	ColumnInvoiceID,
+	ColumnDate,
+	ColumnStatus,
+	ColumnAmount,
+	ColumnCurrency,
+	ColumnProfitAmount,
+	ColumnPaymentScheme,
+	ColumnMaskedPan,
+	ColumnApprovalCode,
+	ColumnRRN,
+	ColumnReference,
+	ColumnShortQrID,
+	ColumnDestination,
+}
+
+type CSVConfig struct {
+	// Headers replaces the default headers of the columns.
+	Headers map[Column]string
+	// Location converts the dates, they are written in the zone they were received in when nil.
+	Location *time.Location
+	// DateLayout is DefaultDateLayout when empty.
+	DateLayout string
+	// DecimalSeparator replaces the point of the amounts, e.g. "," for spreadsheets in the Ukrainian locale.
+	DecimalSeparator string
+	// Columns are written in the given order, DefaultColumns when empty.
+	Columns []Column
+	// Comma is the field delimiter, ',' when zero. Spreadsheets in the Ukrainian locale expect ';'.
+	Comma rune
+	// BOM starts the file with the UTF-8 byte order mark, so spreadsheet tools detect the encoding of
+	// the Ukrainian text.
+	BOM bool
+	// NoHeader skips the header row.
+	NoHeader bool
+	// NoSanitize writes the text values as is. By default a value starting with '=', '+', '-', '@', a tab or
+	// a carriage return is prefixed with a single quote, so a spreadsheet tool does not evaluate it as a formula.
+	NoSanitize bool
+}
+
+// CSVWriter writes statements as CSV rows. Every item of the cancel list is written as a row of its own,
+// right after the statement, with a negative amount, so the amount column sums up to the net amount.
+type CSVWriter struct {
+	w        *csv.Writer
+	location *time.Location
+	layout   string
+	decimal  string
+	columns  []Column
+	sanitize bool
+}
+
+func NewCSVWriter(w io.Writer, config CSVConfig) (*CSVWriter, error) {
+	columns := util.Ternary(len(config.Columns) == 0, DefaultColumns, config.Columns)
+
+	for _, column := range columns {
+		if !isColumn(column) {
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+
+	if config.BOM {
+		if _, err := io.WriteString(w, bom); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	writer := &CSVWriter{
+		w:        csv.NewWriter(w),
+		location: config.Location,
+		columns:  columns,
+		layout:   util.Ternary(config.DateLayout == "", DefaultDateLayout, config.DateLayout),
+		decimal:  config.DecimalSeparator,
+		sanitize: !config.NoSanitize,
+	}
+
+	if config.Comma != 0 {
+		writer.w.Comma = config.Comma
+	}
+
+	if config.NoHeader {
+		return writer, nil
+	}
+
+	header := make([]string, len(columns))
+
+	for i, column := range columns {
+		header[i] = util.Ternary(config.Headers[column] == "", string(column), config.Headers[column])
+	}
+
+	if err := writer.w.Write(header); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return writer, nil
+}
+
+func isColumn(column Column) bool {
+	for _, c := range DefaultColumns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Write writes the statement and its cancel list.
+func (w *CSVWriter) Write(statement monoacquiring.Statement) error {
+	if err := w.w.Write(w.paymentRow(statement)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, cancel := range statement.CancelList {
+		if err := w.w.Write(w.cancelRow(statement, cancel)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// Flush writes the buffered rows, it must be called after the last Write.
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+
+	return errors.WithStack(w.w.Error())
+}
+
+func (w *CSVWriter) paymentRow(statement monoacquiring.Statement) []string {
+	values := map[Column]string{
+		ColumnRowType:       RowTypePayment,
+		ColumnInvoiceID:     statement.InvoiceID,
+		ColumnDate:          w.date(statement.Date),
+		ColumnStatus:        statement.Status.String(),
+		ColumnAmount:        w.amount(statement.Money()),
+		ColumnCurrency:      statement.Currency.Code(),
+		ColumnPaymentScheme: statement.PaymentScheme.String(),
+		ColumnMaskedPan:     statement.MaskedPan,
+		ColumnApprovalCode:  util.PointerValue(statement.ApprovalCode),
+		ColumnRRN:           util.PointerValue(statement.RRN),
+		ColumnReference:     util.PointerValue(statement.Reference),
+		ColumnShortQrID:     util.PointerValue(statement.ShortQrID),
+		ColumnDestination:   util.PointerValue(statement.Destination),
+	}
+
+	if statement.ProfitAmount != nil {
+		values[ColumnProfitAmount] = w.amount(monoacquiring.NewMoney(*statement.ProfitAmount, statement.Currency))
+	}
+
+	return w.row(values)
+}
+
+// cancelRow repeats the fields of the statement identifying the payment, the rest comes from the cancel item.
+func (w *CSVWriter) cancelRow(statement monoacquiring.Statement, cancel monoacquiring.StatementCancel) []string {
+	return w.row(map[Column]string{
+		ColumnRowType:       RowTypeCancel,
+		ColumnInvoiceID:     statement.InvoiceID,
+		ColumnDate:          w.date(cancel.Date),
+		ColumnAmount:        w.amount(monoacquiring.NewMoney(-cancel.Amount, cancel.Currency)),
+		ColumnCurrency:      cancel.Currency.Code(),
+		ColumnPaymentScheme: statement.PaymentScheme.String(),
+		ColumnMaskedPan:     cancel.MaskedPan,
+		ColumnApprovalCode:  util.PointerValue(cancel.ApprovalCode),
+		ColumnRRN:           util.PointerValue(cancel.RRN),
+		ColumnReference:     util.PointerValue(statement.Reference),
+		ColumnShortQrID:     util.PointerValue(statement.ShortQrID),
+		ColumnDestination:   util.PointerValue(statement.Destination),
+	})
+}
+
+func (w *CSVWriter) row(values map[Column]string) []string {
+	row := make([]string, len(w.columns))
+
+	for i, column := range w.columns {
+		row[i] = values[column]
+
+		// the amounts are formatted here, a negative one is not a formula
+		if w.sanitize && column != ColumnAmount && column != ColumnProfitAmount {
+			row[i] = sanitize(row[i])
+		}
+	}
+
+	return row
+}
+
+// sanitize defuses the values spreadsheet tools evaluate as formulas, e.g. a reference "=HYPERLINK(...)".
+func sanitize(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func (w *CSVWriter) amount(m monoacquiring.Money) string {
+	if w.decimal == "" {
+		return m.Decimal()
+	}
+
+	return strings.Replace(m.Decimal(), ".", w.decimal, 1)
+}
+
+func (w *CSVWriter) date(ts monoacquiring.Timestamp) string {
+	if !ts.IsValid() {
+		return ts.Raw
+	}
+
+	t := ts.Time
+	if w.location != nil {
+		t = t.In(w.location)
+	}
+
+	return t.Format(w.layout)
+}
+
+// WriteCSV writes the statements with a CSVWriter.
+func WriteCSV(w io.Writer, statements []monoacquiring.Statement, config CSVConfig) error {
+	writer, err := NewCSVWriter(w, config)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if err = writer.Write(statement); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatements(t *testing.T) []monoacquiring.Statement {
+	t.Helper()
+
+	date, err := monoacquiring.ParseTimestamp("2025-08-11T06:08:52Z")
+	require.NoError(t, err)
+
+	cancelDate, err := monoacquiring.ParseTimestamp("2025-08-12T10:00:00+03:00")
+	require.NoError(t, err)
+
+	return []monoacquiring.Statement{
+		{
+			InvoiceID:     "2205175v4MfatvmUL2oR",
+			MaskedPan:     "444403******1902",
+			Date:          date,
+			Status:        monoacquiring.StatementStatus("success"),
+			PaymentScheme: monoacquiring.StatementPaymentScheme("full"),
+			ApprovalCode:  util.Pointer("662476"),
+			RRN:           util.Pointer("060189181768"),
+			Reference:     util.Pointer("84d0070ee4e44667b31371d8f8813947"),
+			ShortQrID:     util.Pointer("OBJE"),
+			Destination:   util.Pointer("Покупка щастя, \"Розетка\""),
+			ProfitAmount:  util.Pointer(int64(4100)),
+			Amount:        4200,
+			Currency:      monoacquiring.CurrencyUAH,
+			CancelList: []monoacquiring.StatementCancel{
+				{
+					MaskedPan: "444403******1902",
+					Date:      cancelDate,
+					Amount:    1000,
+					Currency:  monoacquiring.CurrencyUAH,
+				},
+			},
+		},
+		{
+			InvoiceID:     "2205175v4MfatvmUL2oS",
+			MaskedPan:     "537541******1234",
+			Status:        monoacquiring.StatementStatus("hold"),
+			PaymentScheme: monoacquiring.StatementPaymentScheme("bnpl_parts_4"),
+			Amount:        150000,
+			Currency:      monoacquiring.CurrencyJPY,
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, WriteCSV(&buf, testStatements(t), CSVConfig{}))
+
+	expected := `type,invoiceId,date,status,amount,ccy,profitAmount,paymentScheme,maskedPan,approvalCode,rrn,reference,shortQrId,destination
+payment,2205175v4MfatvmUL2oR,2025-08-11 06:08:52,success,42.00,UAH,41.00,full,444403******1902,662476,060189181768,84d0070ee4e44667b31371d8f8813947,OBJE,"Покупка щастя, ""Розетка"""
+cancel,2205175v4MfatvmUL2oR,2025-08-12 10:00:00,,-10.00,UAH,,full,444403******1902,,,84d0070ee4e44667b31371d8f8813947,OBJE,"Покупка щастя, ""Розетка"""
+payment,2205175v4MfatvmUL2oS,,hold,150000,JPY,,bnpl_parts_4,537541******1234,,,,,
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteCSV_Config(t *testing.T) {
+	kyiv := time.FixedZone("Kyiv", 3*60*60)
+
+	var buf bytes.Buffer
+
+	err := WriteCSV(&buf, testStatements(t)[:1], CSVConfig{
+		Columns:          []Column{ColumnInvoiceID, ColumnDate, ColumnAmount, ColumnDestination},
+		Headers:          map[Column]string{ColumnInvoiceID: "Рахунок", ColumnAmount: "Сума"},
+		Location:         kyiv,
+		DateLayout:       "02.01.2006 15:04",
+		DecimalSeparator: ",",
+		Comma:            ';',
+		BOM:              true,
+	})
+	require.NoError(t, err)
+
+	expected := "\ufeff" + `Рахунок;date;Сума;destination
+2205175v4MfatvmUL2oR;11.08.2025 09:08;42,00;"Покупка щастя, ""Розетка"""
+2205175v4MfatvmUL2oR;12.08.2025 10:00;-10,00;"Покупка щастя, ""Розетка"""
+`
+
+	assert.Equal(t, expected, buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, WriteCSV(&buf, nil, CSVConfig{Columns: []Column{ColumnInvoiceID}, NoHeader: true}))
+	assert.Empty(t, buf.String())
+
+	err = WriteCSV(&buf, nil, CSVConfig{Columns: []Column{"unknown"}})
+	assert.Error(t, err)
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	writer, err := NewCSVWriter(&buf, CSVConfig{Columns: []Column{ColumnRowType, ColumnInvoiceID}})
+	require.NoError(t, err)
+
+	for _, statement := range testStatements(t) {
+		require.NoError(t, writer.Write(statement))
+	}
+
+	require.NoError(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	assert.Equal(t, []string{
+		"type,invoiceId",
+		"payment,2205175v4MfatvmUL2oR",
+		"cancel,2205175v4MfatvmUL2oR",
+		"payment,2205175v4MfatvmUL2oS",
+	}, lines)
+}
+
+func TestWriteCSV_Sanitize(t *testing.T) {
+	statements := []monoacquiring.Statement{
+		{
+			InvoiceID:   "2205175v4MfatvmUL2oR",
+			Reference:   util.Pointer(`=HYPERLINK("http://example.com","x")`),
+			Destination: util.Pointer("@SUM(1+1)"),
+			Amount:      4200,
+			Currency:    monoacquiring.CurrencyUAH,
+			CancelList: []monoacquiring.StatementCancel{
+				{Amount: 1000, Currency: monoacquiring.CurrencyUAH},
+			},
+		},
+	}
+
+	columns := []Column{ColumnAmount, ColumnReference, ColumnDestination}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, WriteCSV(&buf, statements, CSVConfig{Columns: columns, NoHeader: true}))
+
+	expected := `42.00,"'=HYPERLINK(""http://example.com"",""x"")",'@SUM(1+1)
+-10.00,"'=HYPERLINK(""http://example.com"",""x"")",'@SUM(1+1)
+`
+
+	assert.Equal(t, expected, buf.String(), "the negative amount is not prefixed")
+
+	buf.Reset()
+
+	require.NoError(t, WriteCSV(&buf, statements[:1], CSVConfig{Columns: columns[2:], NoHeader: true, NoSanitize: true}))
+	assert.Equal(t, "@SUM(1+1)\n@SUM(1+1)\n", buf.String())
+}
+
+func TestSanitize(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"84d0070e":   "84d0070e",
+		"=1+1":       "'=1+1",
+		"+380":       "'+380",
+		"-1":         "'-1",
+		"@SUM(A1)":   "'@SUM(A1)",
+		"\t=1":       "'\t=1",
+		"\r=1":       "'\r=1",
+		"Покупка =1": "Покупка =1",
+	}
+
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, sanitize(value))
+		})
+	}
+}
@@ -20,19 +20,20 @@ type GetInvoiceStatusRequest struct {
 type GetInvoiceStatusResponse struct {
 	Destination   *string          `json:"destination,omitempty"`
 	TipsInfo      *TipsInfo        `json:"tipsInfo,omitempty"`
-	FinalAmount   *int             `json:"finalAmount,omitempty"`
-	CreatedDate   *string          `json:"createdDate,omitempty"`
-	ModifiedDate  *string          `json:"modifiedDate,omitempty"`
+	FinalAmount   *int64           `json:"finalAmount,omitempty"`
+	CreatedDate   Timestamp        `json:"createdDate,omitempty"`
+	ModifiedDate  Timestamp        `json:"modifiedDate,omitempty"`
 	Reference     *string          `json:"reference,omitempty"`
 	ErrCode       *string          `json:"errCode,omitempty"`
 	PaymentInfo   *PaymentInfo     `json:"paymentInfo"`
 	FailureReason *string          `json:"failureReason,omitempty"`
 	WalletData    *WalletData      `json:"walletData,omitempty"`
 	InvoiceID     string           `json:"invoiceId"`
-	Status        string           `json:"status"`
+	Status        InvoiceStatus    `json:"status"`
+	PayMethod     PaymentMethod    `json:"payMethod,omitempty"`
 	CancelList    []CancelListItem `json:"cancelList,omitempty"`
 	Amount        int64            `json:"amount"`
-	Currency      int              `json:"ccy"`
+	Currency      Currency         `json:"ccy"`
 }
 
 func (c *Client) GetInvoiceStatus(
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
 	"github.com/go-playground/validator/v10"
@@ -82,9 +83,11 @@ func TestGetInvoiceStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
 	assert.Equal(t, "p2_9ZgpZVsl3", res.InvoiceID)
-	assert.Equal(t, "success", res.Status)
+	assert.True(t, res.Status.IsSuccess())
 	assert.Equal(t, int64(4200), res.Amount)
-	assert.Equal(t, 980, res.Currency)
+	assert.Equal(t, CurrencyUAH, res.Currency)
+	assert.True(t, res.CreatedDate.Equal(time.Date(2025, 7, 17, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, res.CancelList[0].CreatedDate.IsValid())
 	assert.Equal(t, "Неправильний CVV код", util.PointerValue(res.FailureReason))
 	assert.Equal(t, "59", util.PointerValue(res.ErrCode))
 	assert.Len(t, res.CancelList, 1)
@@ -0,0 +1,443 @@
+// Package hold tracks invoices paid with PaymentTypeHold until they are finalized or released, so none of them
+// expires unnoticed and drops the sale.
+package hold
+
+import (
+	"context"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultExpiry is the time monobank keeps a hold for.
+	DefaultExpiry = 9 * 24 * time.Hour
+	// DefaultAutoBefore is the time before the expiry the AutoAction runs at.
+	DefaultAutoBefore = 24 * time.Hour
+)
+
+var ErrNotInHold = errors.New("invoice is not in hold")
+
+type Config struct {
+	Client *monoacquiring.Client
+	Store  Store
+	// Now returns the current time, time.Now when nil.
+	Now func() time.Time
+	// AutoAction runs for a hold without a scheduled action AutoBefore its expiry, ActionNone disables it.
+	AutoAction Action
+	// Expiry is DefaultExpiry when zero. It is counted from the creation of the invoice, which is not later
+	// than the payment, so a hold is never considered younger than it is.
+	Expiry time.Duration
+	// AutoBefore is DefaultAutoBefore when zero.
+	AutoBefore time.Duration
+}
+
+// Manager creates holds, records them in the Store and finalizes or releases them on demand or on schedule.
+type Manager struct {
+	client     *monoacquiring.Client
+	store      Store
+	now        func() time.Time
+	autoAction Action
+	expiry     time.Duration
+	autoBefore time.Duration
+}
+
+func NewManager(config Config) (*Manager, error) {
+	if config.Client == nil || config.Store == nil {
+		return nil, errors.New("hold manager requires a client and a store")
+	}
+
+	if config.AutoAction != ActionNone && !config.AutoAction.IsFinalize() && !config.AutoAction.IsRelease() {
+		return nil, errors.Errorf("unknown hold action %q", config.AutoAction)
+	}
+
+	return &Manager{
+		client:     config.Client,
+		store:      config.Store,
+		now:        util.Ternary(config.Now == nil, time.Now, config.Now),
+		expiry:     util.Ternary(config.Expiry <= 0, DefaultExpiry, config.Expiry),
+		autoBefore: util.Ternary(config.AutoBefore <= 0, DefaultAutoBefore, config.AutoBefore),
+		autoAction: config.AutoAction,
+	}, nil
+}
+
+// CreateInvoice creates the invoice with PaymentTypeHold and tracks it. Like the client, it returns the response
+// along with monoacquiring.ErrPaymentNotRecorded, the hold is tracked then too.
+func (m *Manager) CreateInvoice(
+	ctx context.Context,
+	payload monoacquiring.InvoiceCreateRequest,
+) (*monoacquiring.InvoiceCreateResponse, error) {
+	payload.PaymentType = monoacquiring.PaymentTypeHold
+
+	res, err := m.client.CreateInvoice(ctx, payload)
+	if err != nil && !errors.Is(err, monoacquiring.ErrPaymentNotRecorded) {
+		return nil, err
+	}
+
+	amount := monoacquiring.NewMoney(payload.Amount, currency(payload.Currency))
+
+	return res, m.track(ctx, res.InvoiceID, amount, basket(payload.MerchantPaymentInfo), err)
+}
+
+// TokenPayment pays with PaymentTypeHold and tracks the invoice.
+func (m *Manager) TokenPayment(
+	ctx context.Context,
+	payload monoacquiring.TokenPaymentRequest,
+) (*monoacquiring.TokenPaymentResponse, error) {
+	payload.PaymentType = monoacquiring.PaymentTypeHold
+
+	res, err := m.client.TokenPayment(ctx, payload)
+	if err != nil && !errors.Is(err, monoacquiring.ErrPaymentNotRecorded) {
+		return nil, err
+	}
+
+	amount := monoacquiring.NewMoney(payload.Amount, payload.Currency)
+
+	return res, m.track(ctx, res.InvoiceID, amount, basket(payload.MerchantPaymentInfo), err)
+}
+
+// DirectPayment pays with PaymentTypeHold and tracks the invoice.
+func (m *Manager) DirectPayment(
+	ctx context.Context,
+	payload monoacquiring.DirectPaymentRequest,
+) (*monoacquiring.DirectPaymentResponse, error) {
+	payload.PaymentType = monoacquiring.PaymentTypeHold
+
+	res, err := m.client.DirectPayment(ctx, payload)
+	if err != nil && !errors.Is(err, monoacquiring.ErrPaymentNotRecorded) {
+		return nil, err
+	}
+
+	amount := monoacquiring.NewMoney(payload.Amount, currency(payload.Currency))
+
+	return res, m.track(ctx, res.InvoiceID, amount, basket(payload.MerchantPaymentInfo), err)
+}
+
+// track records the created hold. A hold the idempotency store failed to record was created all the same, it is
+// tracked and its ErrPaymentNotRecorded is returned.
+func (m *Manager) track(
+	ctx context.Context,
+	invoiceID string,
+	amount monoacquiring.Money,
+	items []monoacquiring.BasketOrder,
+	err error,
+) error {
+	if trackErr := m.Track(ctx, invoiceID, amount, items); trackErr != nil {
+		return trackErr
+	}
+
+	return err
+}
+
+func currency(ccy *monoacquiring.Currency) monoacquiring.Currency {
+	return util.Ternary(ccy == nil, monoacquiring.CurrencyUAH, util.PointerValue(ccy))
+}
+
+func basket(info *monoacquiring.MerchantPaymentInfo) []monoacquiring.BasketOrder {
+	if info == nil {
+		return nil
+	}
+
+	return info.BasketOrder
+}
+
+// Track records a hold created without the Manager, items is the basket it was created with.
+func (m *Manager) Track(
+	ctx context.Context,
+	invoiceID string,
+	amount monoacquiring.Money,
+	items []monoacquiring.BasketOrder,
+) error {
+	now := m.now()
+
+	return m.store.Save(ctx, Record{
+		InvoiceID: invoiceID,
+		Amount:    amount,
+		Items:     items,
+		CreatedAt: now,
+		ExpiresAt: now.Add(m.expiry),
+	})
+}
+
+// Status reads the status of the hold from GetInvoiceStatus.
+func (m *Manager) Status(ctx context.Context, invoiceID string) (monoacquiring.InvoiceStatus, error) {
+	res, err := m.client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: invoiceID})
+	if err != nil {
+		return "", err
+	}
+
+	return res.Status, nil
+}
+
+// Expiring returns the tracked holds expiring within the duration, including the expired ones.
+func (m *Manager) Expiring(ctx context.Context, within time.Duration) ([]Record, error) {
+	records, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := m.now().Add(within)
+	expiring := make([]Record, 0, len(records))
+
+	for _, record := range records {
+		if !record.ExpiresAt.After(deadline) {
+			expiring = append(expiring, record)
+		}
+	}
+
+	return expiring, nil
+}
+
+// Finalize charges the amount of the hold, the whole amount when it is nil. The items must be a part of
+// the basket the hold was created with, at the same prices, and add up to the amount.
+func (m *Manager) Finalize(
+	ctx context.Context,
+	invoiceID string,
+	amount *int64,
+	items []monoacquiring.BasketOrder,
+) (*monoacquiring.FinalizeHoldResponse, error) {
+	record, err := m.store.Get(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount, err = checkFinalize(*record, amount, items); err != nil {
+		return nil, err
+	}
+
+	if err = m.checkHold(ctx, invoiceID); err != nil {
+		return nil, err
+	}
+
+	res, err := m.client.FinalizeHold(ctx, monoacquiring.FinalizeHoldRequest{
+		InvoiceID: invoiceID,
+		Amount:    amount,
+		Items:     items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, m.store.Delete(ctx, invoiceID)
+}
+
+// Release cancels the hold, so the money is returned to the customer.
+func (m *Manager) Release(ctx context.Context, invoiceID string) (*monoacquiring.CancelInvoiceResponse, error) {
+	if _, err := m.store.Get(ctx, invoiceID); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkHold(ctx, invoiceID); err != nil {
+		return nil, err
+	}
+
+	res, err := m.client.CancelInvoice(ctx, monoacquiring.CancelInvoiceRequest{InvoiceID: invoiceID})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, m.store.Delete(ctx, invoiceID)
+}
+
+func (m *Manager) checkHold(ctx context.Context, invoiceID string) error {
+	status, err := m.Status(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	if !status.IsHold() {
+		return errors.Wrapf(ErrNotInHold, "invoice %s in status %s", invoiceID, status)
+	}
+
+	return nil
+}
+
+// ScheduleFinalize makes Process finalize the hold at the time, the arguments are checked as by Finalize.
+func (m *Manager) ScheduleFinalize(
+	ctx context.Context,
+	invoiceID string,
+	at time.Time,
+	amount *int64,
+	items []monoacquiring.BasketOrder,
+) error {
+	record, err := m.store.Get(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	if amount, err = checkFinalize(*record, amount, items); err != nil {
+		return err
+	}
+
+	record.Scheduled = ActionFinalize
+	record.ScheduledAt = at
+	record.FinalizeAmount = amount
+	record.FinalizeItems = items
+
+	return m.store.Save(ctx, *record)
+}
+
+// ScheduleRelease makes Process release the hold at the time.
+func (m *Manager) ScheduleRelease(ctx context.Context, invoiceID string, at time.Time) error {
+	record, err := m.store.Get(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	record.Scheduled = ActionRelease
+	record.ScheduledAt = at
+	record.FinalizeAmount = nil
+	record.FinalizeItems = nil
+
+	return m.store.Save(ctx, *record)
+}
+
+// due returns the action to run for the record now.
+func (m *Manager) due(record Record, now time.Time) Action {
+	if record.Scheduled != ActionNone {
+		return util.Ternary(now.Before(record.ScheduledAt), ActionNone, record.Scheduled)
+	}
+
+	if now.Before(record.ExpiresAt.Add(-m.autoBefore)) {
+		return ActionNone
+	}
+
+	return m.autoAction
+}
+
+// Process runs the due actions of the tracked holds. A hold that is no longer in the hold status, e.g. finalized
+// in the merchant cabinet, is forgotten. It goes on after a failed hold and returns the first error.
+func (m *Manager) Process(ctx context.Context) error {
+	records, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var first error
+
+	for _, record := range records {
+		if err = m.process(ctx, record); err != nil && first == nil {
+			first = errors.Wrapf(err, "hold %s", record.InvoiceID)
+		}
+	}
+
+	return first
+}
+
+func (m *Manager) process(ctx context.Context, record Record) error {
+	action := m.due(record, m.now())
+	if action == ActionNone {
+		return nil
+	}
+
+	status, err := m.Status(ctx, record.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.IsHold():
+	case status.IsCreated() || status.IsProcessing():
+		// not paid yet
+		return nil
+	default:
+		return m.store.Delete(ctx, record.InvoiceID)
+	}
+
+	if action.IsRelease() {
+		_, err = m.Release(ctx, record.InvoiceID)
+	} else {
+		_, err = m.Finalize(ctx, record.InvoiceID, record.FinalizeAmount, record.FinalizeItems)
+	}
+
+	return err
+}
+
+// Run calls Process every interval until the context ends.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// errors of a pass are retried by the next one
+		_ = m.Process(ctx)
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkFinalize checks the items against the basket of the hold and returns the amount to finalize.
+func checkFinalize(record Record, amount *int64, items []monoacquiring.BasketOrder) (*int64, error) {
+	if amount != nil && (*amount <= 0 || *amount > record.Amount.Amount) {
+		return nil, errors.Errorf("finalize amount %d is out of the hold amount %d", *amount, record.Amount.Amount)
+	}
+
+	if len(items) == 0 {
+		return amount, nil
+	}
+
+	if err := checkItems(record.Items, items); err != nil {
+		return nil, err
+	}
+
+	b := monoacquiring.NewBasket(record.Amount.Currency)
+
+	for _, item := range items {
+		b.AddItem(item)
+	}
+
+	total, err := b.Total()
+	if err != nil {
+		return nil, err
+	}
+
+	if total > record.Amount.Amount {
+		return nil, errors.Wrapf(monoacquiring.ErrBasketTotalMismatch, "items total %d exceeds the hold amount %d",
+			total, record.Amount.Amount)
+	}
+
+	if amount != nil && *amount != total {
+		return nil, errors.Wrapf(monoacquiring.ErrBasketTotalMismatch, "finalize amount %d, items total %d",
+			*amount, total)
+	}
+
+	return &total, nil
+}
+
+// checkItems checks that the items are a part of the original basket, when the hold was created with one.
+func checkItems(original, items []monoacquiring.BasketOrder) error {
+	if len(original) == 0 {
+		return nil
+	}
+
+	byCode := make(map[string]monoacquiring.BasketOrder, len(original))
+
+	for _, item := range original {
+		byCode[item.Code] = item
+	}
+
+	qty := make(map[string]float64, len(items))
+
+	for _, item := range items {
+		orig, ok := byCode[item.Code]
+		if !ok {
+			return errors.Errorf("finalize item %q is not in the hold basket", item.Code)
+		}
+
+		if item.Sum != orig.Sum {
+			return errors.Errorf("finalize item %q: sum %d, hold sum %d", item.Code, item.Sum, orig.Sum)
+		}
+
+		if qty[item.Code] += item.Qty; qty[item.Code] > orig.Qty {
+			return errors.Errorf("finalize item %q: qty %v exceeds the hold qty %v", item.Code, qty[item.Code], orig.Qty)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package hold
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/monotest"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestManager(t *testing.T, autoAction Action) (*monotest.Server, *Manager, *clock) {
+	t.Helper()
+
+	srv, err := monotest.NewServer()
+	require.NoError(t, err)
+
+	t.Cleanup(srv.Close)
+
+	client, err := monoacquiring.NewClient(srv.Config(), nil, nil)
+	require.NoError(t, err)
+
+	c := &clock{now: time.Date(2025, 8, 11, 12, 0, 0, 0, time.UTC)}
+
+	manager, err := NewManager(Config{
+		Client:     client,
+		Store:      NewMemoryStore(),
+		Now:        c.Now,
+		AutoAction: autoAction,
+	})
+	require.NoError(t, err)
+
+	return srv, manager, c
+}
+
+func testBasket() []monoacquiring.BasketOrder {
+	return []monoacquiring.BasketOrder{
+		{Name: "Хліб", Code: "bread", Qty: 2, Sum: 2550, Total: util.Pointer(int64(5100))},
+		{Name: "Сир", Code: "cheese", Qty: 1, Sum: 4900, Total: util.Pointer(int64(4900))},
+	}
+}
+
+func createHold(t *testing.T, srv *monotest.Server, manager *Manager) string {
+	t.Helper()
+
+	ctx := context.Background()
+
+	res, err := manager.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{
+		Amount:              10000,
+		MerchantPaymentInfo: &monoacquiring.MerchantPaymentInfo{BasketOrder: testBasket()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, srv.Pay(ctx, res.InvoiceID))
+
+	return res.InvoiceID
+}
+
+func TestManager_Finalize(t *testing.T) {
+	srv, manager, _ := newTestManager(t, ActionNone)
+	ctx := context.Background()
+
+	invoiceID := createHold(t, srv, manager)
+
+	status, err := manager.Status(ctx, invoiceID)
+	require.NoError(t, err)
+	assert.True(t, status.IsHold())
+
+	for name, items := range map[string][]monoacquiring.BasketOrder{
+		"unknown item": {{Name: "Вино", Code: "wine", Qty: 1, Sum: 100}},
+		"other sum":    {{Name: "Хліб", Code: "bread", Qty: 1, Sum: 2000}},
+		"over qty":     {{Name: "Хліб", Code: "bread", Qty: 3, Sum: 2550}},
+	} {
+		_, err = manager.Finalize(ctx, invoiceID, nil, items)
+		assert.Error(t, err, name)
+	}
+
+	_, err = manager.Finalize(ctx, invoiceID, util.Pointer(int64(4000)), testBasket()[1:])
+	assert.ErrorIs(t, err, monoacquiring.ErrBasketTotalMismatch)
+
+	res, err := manager.Finalize(ctx, invoiceID, nil, []monoacquiring.BasketOrder{
+		{Name: "Хліб", Code: "bread", Qty: 1, Sum: 2550},
+		{Name: "Сир", Code: "cheese", Qty: 1, Sum: 4900},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.Status.IsSuccess())
+
+	invoice, err := srv.Invoice(invoiceID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7450), util.PointerValue(invoice.FinalAmount))
+
+	_, err = manager.Finalize(ctx, invoiceID, nil, nil)
+	assert.ErrorIs(t, err, ErrNotFound, "forgotten once finalized")
+}
+
+type failingCompleteStore struct {
+	*monoacquiring.MemoryIdempotencyStore
+}
+
+func (failingCompleteStore) Complete(context.Context, string, string) error {
+	return errors.New("store is down")
+}
+
+func TestManager_PaymentNotRecorded(t *testing.T) {
+	srv, err := monotest.NewServer()
+	require.NoError(t, err)
+
+	t.Cleanup(srv.Close)
+
+	config := srv.Config()
+	config.Idempotency = &monoacquiring.IdempotencyPolicy{
+		Store: failingCompleteStore{monoacquiring.NewMemoryIdempotencyStore()},
+	}
+
+	client, err := monoacquiring.NewClient(config, nil, nil)
+	require.NoError(t, err)
+
+	manager, err := NewManager(Config{Client: client, Store: NewMemoryStore()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	res, err := manager.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{
+		Amount:              10000,
+		MerchantPaymentInfo: &monoacquiring.MerchantPaymentInfo{Reference: util.Pointer("order-1")},
+	})
+	require.ErrorIs(t, err, monoacquiring.ErrPaymentNotRecorded)
+	require.NotNil(t, res)
+
+	expiring, err := manager.Expiring(ctx, DefaultExpiry)
+	require.NoError(t, err)
+	require.Len(t, expiring, 1, "the created hold is tracked")
+	assert.Equal(t, res.InvoiceID, expiring[0].InvoiceID)
+}
+
+func TestManager_Release(t *testing.T) {
+	srv, manager, _ := newTestManager(t, ActionNone)
+	ctx := context.Background()
+
+	invoiceID := createHold(t, srv, manager)
+
+	_, err := manager.Release(ctx, invoiceID)
+	require.NoError(t, err)
+
+	invoice, err := srv.Invoice(invoiceID)
+	require.NoError(t, err)
+	assert.True(t, invoice.Status.IsReversed())
+}
+
+func TestManager_Process(t *testing.T) {
+	srv, manager, c := newTestManager(t, ActionRelease)
+	ctx := context.Background()
+
+	scheduled := createHold(t, srv, manager)
+	expiring := createHold(t, srv, manager)
+	finalized := createHold(t, srv, manager)
+	unpaid, err := manager.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{Amount: 100})
+	require.NoError(t, err)
+
+	require.NoError(t, manager.ScheduleFinalize(ctx, scheduled, c.now.Add(time.Hour), util.Pointer(int64(5000)), nil))
+	require.NoError(t, srv.SetStatus(ctx, finalized, monoacquiring.InvoiceStatusSuccess))
+
+	require.NoError(t, manager.Process(ctx))
+
+	for _, invoiceID := range []string{scheduled, expiring, finalized} {
+		invoice, err := srv.Invoice(invoiceID)
+		require.NoError(t, err)
+		assert.NotEqual(t, monoacquiring.InvoiceStatusReversed, invoice.Status, "nothing is due yet")
+	}
+
+	c.now = c.now.Add(2 * time.Hour)
+
+	require.NoError(t, manager.Process(ctx))
+
+	invoice, err := srv.Invoice(scheduled)
+	require.NoError(t, err)
+	assert.True(t, invoice.Status.IsSuccess())
+	assert.Equal(t, int64(5000), util.PointerValue(invoice.FinalAmount))
+
+	expiringRecords, err := manager.Expiring(ctx, DefaultExpiry)
+	require.NoError(t, err)
+	assert.Len(t, expiringRecords, 3, "expiring, finalized and unpaid")
+
+	c.now = c.now.Add(DefaultExpiry - DefaultAutoBefore)
+
+	require.NoError(t, manager.Process(ctx))
+
+	invoice, err = srv.Invoice(expiring)
+	require.NoError(t, err)
+	assert.True(t, invoice.Status.IsReversed(), "auto released")
+
+	invoice, err = srv.Invoice(finalized)
+	require.NoError(t, err)
+	assert.True(t, invoice.Status.IsSuccess(), "finalized elsewhere")
+
+	records, err := manager.Expiring(ctx, DefaultExpiry)
+	require.NoError(t, err)
+	require.Len(t, records, 1, "unpaid invoice is kept")
+	assert.Equal(t, unpaid.InvoiceID, records[0].InvoiceID)
+}
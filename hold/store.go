@@ -0,0 +1,118 @@
+package hold
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+)
+
+var ErrNotFound = errors.New("hold not found")
+
+type Action string
+
+const (
+	ActionNone     Action = ""
+	ActionFinalize Action = "finalize"
+	ActionRelease  Action = "release"
+)
+
+func (a Action) String() string {
+	return string(a)
+}
+
+func (a Action) IsFinalize() bool {
+	return a == ActionFinalize
+}
+
+func (a Action) IsRelease() bool {
+	return a == ActionRelease
+}
+
+// Record is a hold tracked by the Manager.
+type Record struct {
+	CreatedAt time.Time
+	// ExpiresAt is CreatedAt plus Config.Expiry, the hold is released by monobank after it.
+	ExpiresAt time.Time
+	// ScheduledAt is the time Manager.Process runs the scheduled action at.
+	ScheduledAt time.Time
+	// FinalizeAmount is the amount of a scheduled finalize, the whole amount when nil.
+	FinalizeAmount *int64
+	InvoiceID      string
+	Scheduled      Action
+	// Items is the basket the hold was created with, finalize items are checked against it.
+	Items         []monoacquiring.BasketOrder
+	FinalizeItems []monoacquiring.BasketOrder
+	Amount        monoacquiring.Money
+}
+
+// Store persists the tracked holds.
+type Store interface {
+	// Save inserts the record or replaces the record of its invoice.
+	Save(ctx context.Context, record Record) error
+	// Get fails with ErrNotFound when the invoice is not tracked.
+	Get(ctx context.Context, invoiceID string) (*Record, error)
+	Delete(ctx context.Context, invoiceID string) error
+	List(ctx context.Context) ([]Record, error)
+}
+
+// MemoryStore is an in-process Store, it does not survive a restart and serves tests.
+type MemoryStore struct {
+	records map[string]Record
+	mu      sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Save(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.InvoiceID] = record
+
+	return nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, invoiceID string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[invoiceID]
+	if !ok {
+		return nil, errors.Wrap(ErrNotFound, invoiceID)
+	}
+
+	return &record, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, invoiceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, invoiceID)
+
+	return nil
+}
+
+// List returns the records ordered by expiry.
+func (s *MemoryStore) List(_ context.Context) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]Record, 0, len(s.records))
+
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+
+	slices.SortFunc(records, func(a, b Record) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+
+	return records, nil
+}
@@ -0,0 +1,256 @@
+package monoacquiring
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultIdempotencyLockTimeout = time.Minute
+
+	// idempotencyLookupMargin widens the statement period of the lookup for the clock skew.
+	idempotencyLookupMargin = time.Minute
+)
+
+var (
+	ErrPaymentExists   = errors.New("payment with the idempotency key already exists")
+	ErrPaymentInFlight = errors.New("payment with the idempotency key is in flight")
+	// ErrIdempotencyKeyRequired is returned without calling the API for a payment without a reference.
+	ErrIdempotencyKeyRequired = errors.New("idempotency key is required, set MerchantPaymentInfo.Reference")
+	// ErrPaymentNotRecorded is returned together with the response when the payment was created, but the Store
+	// failed to complete its record. The payment must not be repeated, the record left in flight is resolved by
+	// the statement lookup of the next attempt with the key.
+	ErrPaymentNotRecorded = errors.New("payment is not recorded in the idempotency store")
+)
+
+// IdempotencyPolicy makes CreateInvoice, TokenPayment, DirectPayment and SyncPayment safe to repeat after a crash.
+//
+// The idempotency key is MerchantPaymentInfo.Reference, it is required and must be kept with the order, so a call
+// repeated after a crash has the same key, e.g. one of NewIdempotencyKey. Every attempt is recorded in the Store
+// before it is sent. A call with the key of a completed attempt, or of an attempt found in GetStatement by its
+// reference, returns an IdempotencyError instead of charging twice.
+type IdempotencyPolicy struct {
+	Store IdempotencyStore `validate:"required"`
+	// LockTimeout is the age of an in-flight attempt after which it is considered interrupted and looked up
+	// in GetStatement, DefaultIdempotencyLockTimeout when zero. It must exceed the duration of a call.
+	LockTimeout time.Duration `validate:"min=0"`
+}
+
+func (p IdempotencyPolicy) lockTimeout() time.Duration {
+	return util.Ternary(p.LockTimeout <= 0, DefaultIdempotencyLockTimeout, p.LockTimeout)
+}
+
+type IdempotencyRecord struct {
+	StartedAt time.Time
+	Key       string
+	// Operation is one of the Operation* constants.
+	Operation string
+	// InvoiceID is empty while the attempt is in flight.
+	InvoiceID string
+}
+
+// IdempotencyStore persists the payment attempts, it must be shared by all processes using the same keys.
+type IdempotencyStore interface {
+	// Reserve atomically stores the record unless a record with its key exists, which is returned instead.
+	Reserve(ctx context.Context, record IdempotencyRecord) (*IdempotencyRecord, error)
+	// Complete sets the invoice id of the record.
+	Complete(ctx context.Context, key, invoiceID string) error
+	// Delete removes the record, it is called when monobank rejected the attempt, so the key can be used again.
+	Delete(ctx context.Context, key string) error
+}
+
+// IdempotencyError is returned instead of repeating a payment, it wraps ErrPaymentExists or ErrPaymentInFlight.
+type IdempotencyError struct {
+	Key string
+	// InvoiceID is the invoice of the earlier attempt, it is empty when the attempt is still in flight.
+	InvoiceID string
+}
+
+func (e *IdempotencyError) Error() string {
+	if e.InvoiceID == "" {
+		return fmt.Sprintf("%s: %s", e.Unwrap().Error(), e.Key)
+	}
+
+	return fmt.Sprintf("%s: %s (invoice: %s)", e.Unwrap().Error(), e.Key, e.InvoiceID)
+}
+
+func (e *IdempotencyError) Unwrap() error {
+	return util.Ternary(e.InvoiceID == "", ErrPaymentInFlight, ErrPaymentExists)
+}
+
+// NewIdempotencyKey returns a random key, store it with the order before the payment to repeat it after a crash.
+func NewIdempotencyKey() string {
+	return newRequestID()
+}
+
+// beginAttempt reserves the idempotency key of the payment, it returns an empty key when the client has
+// no IdempotencyPolicy.
+func (c *Client) beginAttempt(ctx context.Context, operation string, info *MerchantPaymentInfo) (string, error) {
+	policy := c.cnf.Idempotency
+	if policy == nil {
+		return "", nil
+	}
+
+	if info == nil || util.PointerValue(info.Reference) == "" {
+		return "", errors.WithStack(ErrIdempotencyKeyRequired)
+	}
+
+	record := IdempotencyRecord{Key: *info.Reference, Operation: operation, StartedAt: time.Now()}
+
+	existing, err := policy.Store.Reserve(ctx, record)
+	if err != nil {
+		return "", err
+	}
+
+	if existing == nil {
+		return record.Key, nil
+	}
+
+	if existing.InvoiceID != "" {
+		return "", &IdempotencyError{Key: existing.Key, InvoiceID: existing.InvoiceID}
+	}
+
+	if time.Since(existing.StartedAt) < policy.lockTimeout() {
+		return "", &IdempotencyError{Key: existing.Key}
+	}
+
+	return c.resumeAttempt(ctx, *existing, record)
+}
+
+// resumeAttempt looks up the interrupted attempt in the statement, it takes the key over when nothing was paid.
+func (c *Client) resumeAttempt(ctx context.Context, interrupted, record IdempotencyRecord) (string, error) {
+	store := c.cnf.Idempotency.Store
+
+	from := interrupted.StartedAt.Add(-idempotencyLookupMargin)
+
+	for statement, err := range c.StatementIter(ctx, from, time.Now(), StatementIterOptions{}) {
+		if err != nil {
+			return "", err
+		}
+
+		if util.PointerValue(statement.Reference) != interrupted.Key {
+			continue
+		}
+
+		if err = store.Complete(ctx, interrupted.Key, statement.InvoiceID); err != nil {
+			return "", err
+		}
+
+		return "", &IdempotencyError{Key: interrupted.Key, InvoiceID: statement.InvoiceID}
+	}
+
+	if err := store.Delete(ctx, interrupted.Key); err != nil {
+		return "", err
+	}
+
+	existing, err := store.Reserve(ctx, record)
+	if err != nil {
+		return "", err
+	}
+
+	if existing != nil {
+		// another process took the key over first
+		return "", &IdempotencyError{Key: existing.Key, InvoiceID: existing.InvoiceID}
+	}
+
+	return record.Key, nil
+}
+
+// endAttempt records the outcome of the attempt. An attempt without a response stays in flight, only
+// a response of monobank tells whether the payment was created. A failure to record a created payment is
+// ErrPaymentNotRecorded, the caller returns it along with the response.
+func (c *Client) endAttempt(ctx context.Context, key, invoiceID string, err error) error {
+	if key == "" {
+		return err
+	}
+
+	store := c.cnf.Idempotency.Store
+
+	if err == nil {
+		if err = store.Complete(ctx, key, invoiceID); err != nil {
+			return errors.Wrapf(ErrPaymentNotRecorded, "failed to complete idempotency key %q: %v", key, err)
+		}
+
+		return nil
+	}
+
+	if isRejected(err) {
+		// a record left behind is resolved by the statement lookup of the next attempt
+		_ = store.Delete(ctx, key)
+	}
+
+	return err
+}
+
+// isRejected reports whether monobank refused the request, so no invoice was created.
+func isRejected(err error) bool {
+	var reqErr *RequestError
+
+	if !errors.As(err, &reqErr) {
+		return false
+	}
+
+	return !errors.Is(err, ErrInternalHTTPStatus) && !errors.Is(err, ErrUnexpectedHTTPStatus)
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore, it does not survive a restart and serves tests.
+type MemoryIdempotencyStore struct {
+	records map[string]IdempotencyRecord
+	mu      sync.Mutex
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]IdempotencyRecord)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(_ context.Context, record IdempotencyRecord) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[record.Key]; ok {
+		return &existing, nil
+	}
+
+	s.records[record.Key] = record
+
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key, invoiceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return errors.Errorf("idempotency key %q is not reserved", key)
+	}
+
+	record.InvoiceID = invoiceID
+	s.records[key] = record
+
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+// Get returns the record of the key, it is false when the key is not reserved.
+func (s *MemoryIdempotencyStore) Get(key string) (IdempotencyRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+
+	return record, ok
+}
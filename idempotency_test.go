@@ -0,0 +1,241 @@
+package monoacquiring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type idempotencyServer struct {
+	// mode is "ok", "crash" or "reject".
+	mode      atomic.Value
+	reference atomic.Value
+	// statement is the reference listed by GetStatement, empty for an empty statement.
+	statement string
+	creates   atomic.Int32
+}
+
+func newIdempotencyServer(t *testing.T, statement string) (*idempotencyServer, *Client, *MemoryIdempotencyStore) {
+	t.Helper()
+
+	s := &idempotencyServer{statement: statement}
+	s.mode.Store("ok")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/create", func(w http.ResponseWriter, r *http.Request) {
+		s.creates.Add(1)
+
+		var payload InvoiceCreateRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.MerchantPaymentInfo == nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		s.reference.Store(util.PointerValue(payload.MerchantPaymentInfo.Reference))
+
+		switch s.mode.Load() {
+		case "crash":
+			panic(http.ErrAbortHandler)
+		case "reject":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"errCode": "BAD_REQUEST", "errText": "invalid amount"}`)
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3", "pageUrl": "https://pay.mbnk.biz/p2_9ZgpZVsl3"}`)
+		}
+	})
+	mux.HandleFunc("/api/merchant/statement", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		if s.statement == "" {
+			_, _ = fmt.Fprint(w, `{"list": []}`)
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"list": [
+  {"invoiceId": "other", "reference": "other", "amount": 100, "ccy": 980},
+  {"invoiceId": "p2_found", "reference": %q, "amount": 100, "ccy": 980}
+]}`, s.statement)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	store := NewMemoryIdempotencyStore()
+
+	client, err := NewClient(Config{
+		APIKey:      "test",
+		BaseURL:     srv.URL,
+		Idempotency: &IdempotencyPolicy{Store: store, LockTimeout: time.Nanosecond},
+	}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	return s, client, store
+}
+
+func TestIdempotency_Completed(t *testing.T) {
+	srv, client, store := newIdempotencyServer(t, "")
+
+	key := NewIdempotencyKey()
+	payload := InvoiceCreateRequest{
+		Amount:              100,
+		MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer(key)},
+	}
+
+	res, err := client.CreateInvoice(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Equal(t, "p2_9ZgpZVsl3", res.InvoiceID)
+	assert.Equal(t, key, srv.reference.Load(), "the key is sent as the reference")
+
+	record, ok := store.Get(key)
+	require.True(t, ok)
+	assert.Equal(t, OperationCreateInvoice, record.Operation)
+	assert.Equal(t, "p2_9ZgpZVsl3", record.InvoiceID)
+
+	_, err = client.CreateInvoice(context.Background(), payload)
+	require.ErrorIs(t, err, ErrPaymentExists)
+
+	var idempotencyErr *IdempotencyError
+
+	require.True(t, errors.As(err, &idempotencyErr))
+	assert.Equal(t, "p2_9ZgpZVsl3", idempotencyErr.InvoiceID)
+	assert.Equal(t, int32(1), srv.creates.Load(), "not sent twice")
+}
+
+func TestIdempotency_KeyRequired(t *testing.T) {
+	srv, client, _ := newIdempotencyServer(t, "")
+
+	for name, info := range map[string]*MerchantPaymentInfo{
+		"no info":         nil,
+		"empty reference": {Reference: util.Pointer("")},
+	} {
+		_, err := client.CreateInvoice(context.Background(), InvoiceCreateRequest{Amount: 100, MerchantPaymentInfo: info})
+		assert.ErrorIs(t, err, ErrIdempotencyKeyRequired, name)
+	}
+
+	assert.Zero(t, srv.creates.Load(), "not sent")
+}
+
+func TestIdempotency_Interrupted(t *testing.T) {
+	for name, val := range map[string]struct {
+		Statement string
+		InvoiceID string
+		Creates   int32
+	}{
+		"paid":     {Statement: "order-1", InvoiceID: "p2_found", Creates: 1},
+		"not paid": {InvoiceID: "p2_9ZgpZVsl3", Creates: 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv, client, store := newIdempotencyServer(t, val.Statement)
+			srv.mode.Store("crash")
+
+			payload := InvoiceCreateRequest{
+				Amount:              100,
+				MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer("order-1")},
+			}
+
+			_, err := client.CreateInvoice(context.Background(), payload)
+			require.Error(t, err)
+
+			record, ok := store.Get("order-1")
+			require.True(t, ok, "in flight")
+			assert.Empty(t, record.InvoiceID)
+
+			srv.mode.Store("ok")
+
+			res, err := client.CreateInvoice(context.Background(), payload)
+
+			if val.Statement != "" {
+				require.ErrorIs(t, err, ErrPaymentExists)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, val.InvoiceID, res.InvoiceID)
+			}
+
+			record, _ = store.Get("order-1")
+			assert.Equal(t, val.InvoiceID, record.InvoiceID)
+			assert.Equal(t, val.Creates, srv.creates.Load())
+		})
+	}
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+
+	_, err := store.Reserve(context.Background(), IdempotencyRecord{Key: "order-1", StartedAt: time.Now()})
+	require.NoError(t, err)
+
+	client, err := NewClient(Config{
+		APIKey:      "test",
+		BaseURL:     DefaultBaseURL,
+		Idempotency: &IdempotencyPolicy{Store: store},
+	}, nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.TokenPayment(context.Background(), TokenPaymentRequest{
+		CardToken:           "token",
+		Amount:              100,
+		Currency:            CurrencyUAH,
+		InitiationKind:      "merchant",
+		MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer("order-1")},
+	})
+	assert.ErrorIs(t, err, ErrPaymentInFlight)
+}
+
+func TestIdempotency_Rejected(t *testing.T) {
+	srv, client, store := newIdempotencyServer(t, "")
+	srv.mode.Store("reject")
+
+	payload := InvoiceCreateRequest{
+		Amount:              100,
+		MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer("order-1")},
+	}
+
+	_, err := client.CreateInvoice(context.Background(), payload)
+	require.ErrorIs(t, err, ErrBadRequestHTTPStatus)
+
+	_, ok := store.Get("order-1")
+	assert.False(t, ok, "the key can be used again")
+}
+
+type failingCompleteStore struct {
+	*MemoryIdempotencyStore
+}
+
+func (failingCompleteStore) Complete(context.Context, string, string) error {
+	return errors.New("store is down")
+}
+
+func TestIdempotency_CompleteFailed(t *testing.T) {
+	srv, client, store := newIdempotencyServer(t, "")
+	client.cnf.Idempotency.Store = failingCompleteStore{store}
+
+	payload := InvoiceCreateRequest{
+		Amount:              100,
+		MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer("order-1")},
+	}
+
+	res, err := client.CreateInvoice(context.Background(), payload)
+	require.ErrorIs(t, err, ErrPaymentNotRecorded)
+	assert.ErrorContains(t, err, "store is down")
+	require.NotNil(t, res, "the created payment is returned with the error")
+	assert.Equal(t, "p2_9ZgpZVsl3", res.InvoiceID)
+
+	record, ok := store.Get("order-1")
+	require.True(t, ok)
+	assert.Empty(t, record.InvoiceID, "the record stays in flight")
+	assert.Equal(t, int32(1), srv.creates.Load())
+}
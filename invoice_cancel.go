@@ -33,9 +33,9 @@ type CancelInvoiceRequest struct {
 }
 
 type CancelInvoiceResponse struct {
-	Status       string `json:"status"` // TODO enum
-	CreatedDate  string `json:"createdDate"`
-	ModifiedDate string `json:"modifiedDate"`
+	Status       CancelListItemStatus `json:"status"`
+	CreatedDate  Timestamp            `json:"createdDate"`
+	ModifiedDate Timestamp            `json:"modifiedDate"`
 }
 
 func (c *Client) CancelInvoice(ctx context.Context, payload CancelInvoiceRequest) (*CancelInvoiceResponse, error) {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
@@ -40,9 +41,9 @@ func TestCancelInvoice(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
-	assert.Equal(t, "processing", res.Status)
-	assert.Equal(t, "2025-07-17T12:00:00+03:00", res.CreatedDate)
-	assert.Equal(t, "2025-07-17T14:00:00+03:00", res.ModifiedDate)
+	assert.True(t, res.Status.IsProcessing())
+	assert.Equal(t, "2025-07-17T12:00:00+03:00", res.CreatedDate.Raw)
+	assert.Equal(t, 2*time.Hour, res.ModifiedDate.Sub(res.CreatedDate.Time))
 }
 
 func TestCancelInvoice_Validation(t *testing.T) {
@@ -18,7 +18,7 @@ import (
 
 type InvoiceCreateRequest struct {
 	SaveCardData        *SaveCardData        `json:"saveCardData,omitempty"`
-	Currency            *int                 `json:"ccy,omitempty" validate:"omitempty,iso4217_numeric"`
+	Currency            *Currency            `json:"ccy,omitempty" validate:"omitempty,iso4217_numeric"`
 	MerchantPaymentInfo *MerchantPaymentInfo `json:"merchantPaymInfo,omitempty" validate:"omitempty"`
 	RedirectURL         *string              `json:"redirectUrl,omitempty" validate:"omitempty,http_url"`
 	WebHookURL          *string              `json:"webHookUrl,omitempty" validate:"omitempty,http_url"`
@@ -48,6 +48,11 @@ func (c *Client) CreateInvoice(ctx context.Context, payload InvoiceCreateRequest
 		return nil, errors.WithStack(err)
 	}
 
+	key, err := c.beginAttempt(ctx, OperationCreateInvoice, payload.MerchantPaymentInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := new(bytes.Buffer)
 	if err = json.NewEncoder(buf).Encode(payload); err != nil {
 		return nil, errors.Wrap(err, "failed to marshal create invoice request")
@@ -60,7 +65,14 @@ func (c *Client) CreateInvoice(ctx context.Context, payload InvoiceCreateRequest
 
 	var result InvoiceCreateResponse
 
-	if err = c.doReq(req, &result); err != nil {
+	err = c.doReq(req, &result)
+	err = c.endAttempt(ctx, key, result.InvoiceID, err)
+
+	if errors.Is(err, ErrPaymentNotRecorded) {
+		return &result, err
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,100 @@
+package monoacquiring
+
+const (
+	InvoiceStatusCreated    InvoiceStatus = "created"
+	InvoiceStatusProcessing InvoiceStatus = "processing"
+	InvoiceStatusHold       InvoiceStatus = "hold"
+	InvoiceStatusSuccess    InvoiceStatus = "success"
+	InvoiceStatusFailure    InvoiceStatus = "failure"
+	InvoiceStatusReversed   InvoiceStatus = "reversed"
+	InvoiceStatusExpired    InvoiceStatus = "expired"
+)
+
+// InvoiceStatus is the status of an invoice, shared by the invoice status, payment responses and webhooks.
+type InvoiceStatus string
+
+// invoiceTransitions lists the statuses an invoice can move to from each status.
+// A partial refund keeps success, only the full one makes the invoice reversed.
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusCreated: {
+		InvoiceStatusProcessing,
+		InvoiceStatusHold,
+		InvoiceStatusSuccess,
+		InvoiceStatusFailure,
+		InvoiceStatusExpired,
+	},
+	InvoiceStatusProcessing: {InvoiceStatusHold, InvoiceStatusSuccess, InvoiceStatusFailure},
+	InvoiceStatusHold:       {InvoiceStatusSuccess, InvoiceStatusReversed},
+	InvoiceStatusSuccess:    {InvoiceStatusReversed},
+	InvoiceStatusFailure:    nil,
+	InvoiceStatusReversed:   nil,
+	InvoiceStatusExpired:    nil,
+}
+
+// CanTransition reports whether an invoice in the from status can move to the to status.
+// Staying in the same known status is allowed, e.g. a repeated webhook or a partial refund.
+func CanTransition(from, to InvoiceStatus) bool {
+	next, ok := invoiceTransitions[from]
+	if !ok || !to.IsValid() {
+		return false
+	}
+
+	if from == to {
+		return true
+	}
+
+	for _, status := range next {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (is InvoiceStatus) String() string {
+	return string(is)
+}
+
+// IsValid reports whether the status is one of the documented ones.
+func (is InvoiceStatus) IsValid() bool {
+	_, ok := invoiceTransitions[is]
+
+	return ok
+}
+
+// IsTerminal reports whether the invoice can not change its status anymore.
+// A successful invoice is not terminal, it can still be reversed.
+func (is InvoiceStatus) IsTerminal() bool {
+	next, ok := invoiceTransitions[is]
+
+	return ok && len(next) == 0
+}
+
+func (is InvoiceStatus) IsCreated() bool {
+	return is == InvoiceStatusCreated
+}
+
+func (is InvoiceStatus) IsProcessing() bool {
+	return is == InvoiceStatusProcessing
+}
+
+func (is InvoiceStatus) IsHold() bool {
+	return is == InvoiceStatusHold
+}
+
+func (is InvoiceStatus) IsSuccess() bool {
+	return is == InvoiceStatusSuccess
+}
+
+func (is InvoiceStatus) IsFailure() bool {
+	return is == InvoiceStatusFailure
+}
+
+func (is InvoiceStatus) IsReversed() bool {
+	return is == InvoiceStatusReversed
+}
+
+func (is InvoiceStatus) IsExpired() bool {
+	return is == InvoiceStatusExpired
+}
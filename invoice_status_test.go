@@ -0,0 +1,56 @@
+package monoacquiring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoiceStatus(t *testing.T) {
+	c := InvoiceStatus("created")
+	assert.Equal(t, "created", c.String())
+	assert.True(t, c.IsCreated())
+	assert.True(t, c.IsValid())
+	assert.False(t, c.IsTerminal())
+
+	s := InvoiceStatus("success")
+	assert.True(t, s.IsSuccess())
+	assert.False(t, s.IsTerminal(), "can be reversed")
+
+	for _, status := range []InvoiceStatus{InvoiceStatusFailure, InvoiceStatusReversed, InvoiceStatusExpired} {
+		assert.True(t, status.IsTerminal(), status)
+	}
+
+	u := InvoiceStatus("unknown")
+	assert.False(t, u.IsValid())
+	assert.False(t, u.IsTerminal())
+}
+
+func TestCanTransition(t *testing.T) {
+	for name, val := range map[string]struct {
+		From     InvoiceStatus
+		To       InvoiceStatus
+		Expected bool
+	}{
+		"paid":             {From: InvoiceStatusCreated, To: InvoiceStatusSuccess, Expected: true},
+		"processed":        {From: InvoiceStatusProcessing, To: InvoiceStatusSuccess, Expected: true},
+		"held":             {From: InvoiceStatusProcessing, To: InvoiceStatusHold, Expected: true},
+		"finalized":        {From: InvoiceStatusHold, To: InvoiceStatusSuccess, Expected: true},
+		"hold cancelled":   {From: InvoiceStatusHold, To: InvoiceStatusReversed, Expected: true},
+		"refunded":         {From: InvoiceStatusSuccess, To: InvoiceStatusReversed, Expected: true},
+		"expired":          {From: InvoiceStatusCreated, To: InvoiceStatusExpired, Expected: true},
+		"repeated":         {From: InvoiceStatusSuccess, To: InvoiceStatusSuccess, Expected: true},
+		"back to process":  {From: InvoiceStatusSuccess, To: InvoiceStatusProcessing, Expected: false},
+		"after failure":    {From: InvoiceStatusFailure, To: InvoiceStatusSuccess, Expected: false},
+		"after reversal":   {From: InvoiceStatusReversed, To: InvoiceStatusSuccess, Expected: false},
+		"refund unpaid":    {From: InvoiceStatusCreated, To: InvoiceStatusReversed, Expected: false},
+		"expire paid":      {From: InvoiceStatusSuccess, To: InvoiceStatusExpired, Expected: false},
+		"unknown from":     {From: InvoiceStatus("unknown"), To: InvoiceStatusSuccess, Expected: false},
+		"unknown to":       {From: InvoiceStatusCreated, To: InvoiceStatus("unknown"), Expected: false},
+		"unknown repeated": {From: InvoiceStatus("unknown"), To: InvoiceStatus("unknown"), Expected: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, val.Expected, CanTransition(val.From, val.To))
+		})
+	}
+}
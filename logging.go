@@ -0,0 +1,191 @@
+package monoacquiring
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redacted = "***"
+
+var (
+	redactedFields = map[string]struct{}{
+		"pan":       {},
+		"cvv":       {},
+		"cavv":      {},
+		"tavv":      {},
+		"cardtoken": {},
+		// Apple Pay and Google Pay payment data
+		"token":      {},
+		"cryptogram": {},
+	}
+
+	redactedHeaders = map[string]struct{}{
+		"X-Token": {},
+	}
+)
+
+// LoggingMiddleware logs every attempt with the operation, method, path, status, latency and errCode.
+// At debug level it also logs headers and bodies with card data and the token masked, except the successful
+// response body of StreamStatement.
+// Config.Logger adds this middleware after the ones from Config.Middlewares.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+			var (
+				ctx   = req.Context()
+				debug = logger.Enabled(ctx, slog.LevelDebug)
+				start = time.Now()
+				attrs = []slog.Attr{
+					slog.String("operation", operation),
+					slog.String("method", req.Method),
+					slog.String("path", req.URL.Path),
+				}
+			)
+
+			if debug {
+				attrs = append(attrs,
+					slog.Any("request_headers", redactHeader(req.Header)),
+					slog.String("request_body", redactBody(requestBody(req))),
+				)
+			}
+
+			res, err := next.RoundTrip(operation, req)
+
+			attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, slog.LevelError, "monobank acquiring request failed", attrs...)
+
+				return res, err
+			}
+
+			attrs = append(attrs, slog.Int("status", res.StatusCode))
+
+			level := slog.LevelInfo
+
+			// the body of a streamed statement is not logged, reading it here would buffer the whole list
+			if res.StatusCode >= http.StatusBadRequest || debug && !isStreaming(req) {
+				body := responseBody(res)
+
+				if res.StatusCode >= http.StatusBadRequest {
+					level = slog.LevelWarn
+
+					var errorData errorData
+
+					if json.Unmarshal(body, &errorData) == nil && errorData.Code != "" {
+						attrs = append(attrs, slog.String("err_code", errorData.Code))
+					}
+				}
+
+				if debug {
+					attrs = append(attrs, slog.String("response_body", redactBody(body)))
+				}
+			}
+
+			logger.LogAttrs(ctx, level, "monobank acquiring request", attrs...)
+
+			return res, nil
+		})
+	}
+}
+
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	data, _ := io.ReadAll(body)
+
+	return data
+}
+
+// responseBody reads the body and puts it back, so the client can still decode it.
+func responseBody(res *http.Response) []byte {
+	data, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	res.Body = io.NopCloser(bytes.NewReader(data))
+
+	return data
+}
+
+func redactHeader(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+
+	for key, values := range header {
+		if _, ok := redactedHeaders[http.CanonicalHeaderKey(key)]; ok {
+			result[key] = redacted
+
+			continue
+		}
+
+		result[key] = strings.Join(values, ", ")
+	}
+
+	return result
+}
+
+func redactBody(body []byte) string {
+	body = bytes.TrimSpace(body)
+
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data any
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&data); err != nil {
+		return "<non-json body, " + strconv.Itoa(len(body)) + " bytes>"
+	}
+
+	masked, err := json.Marshal(redactValue(data))
+	if err != nil {
+		return ""
+	}
+
+	return string(masked)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if _, ok := redactedFields[strings.ToLower(key)]; ok && item != nil {
+				v[key] = redacted
+
+				continue
+			}
+
+			v[key] = redactValue(item)
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,248 @@
+package monoacquiring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogging_Redaction(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/payment-direct", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3", "status": "success", "walletData": {"cardToken": "67XZtXdR4NpKU3"}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client, err := NewClient(Config{APIKey: "secret-token", BaseURL: srv.URL, Logger: logger}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	res, err := client.DirectPayment(context.Background(), DirectPaymentRequest{
+		Amount: 4200,
+		Card: DirectPaymentCard{
+			PAN:        "4444111122223333",
+			Expiration: "0642",
+			CVV:        "123",
+		},
+		MerchantPaymentInfo: &MerchantPaymentInfo{Reference: util.Pointer("84d0070ee4e44667")},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "p2_9ZgpZVsl3", res.InvoiceID, "the response must still be decoded")
+
+	output := buf.String()
+
+	assert.NotContains(t, output, "4444111122223333")
+	assert.NotContains(t, output, `\"cvv\":\"123\"`)
+	assert.NotContains(t, output, "secret-token")
+	assert.NotContains(t, output, "67XZtXdR4NpKU3")
+	assert.Contains(t, output, "84d0070ee4e44667")
+
+	records := decodeLogRecords(t, buf)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, OperationDirectPayment, records[0]["operation"])
+	assert.Equal(t, http.MethodPost, records[0]["method"])
+	assert.Equal(t, "/api/merchant/invoice/payment-direct", records[0]["path"])
+	assert.Equal(t, float64(http.StatusOK), records[0]["status"])
+	assert.Contains(t, records[0], "latency")
+	assert.Equal(t, "***", records[0]["request_headers"].(map[string]any)["X-Token"])
+}
+
+func TestLogging_RedactionWallet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/sync-payment", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3", "status": "success"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client, err := NewClient(Config{APIKey: "secret-token", BaseURL: srv.URL, Logger: logger}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	for name, payload := range map[string]SyncPaymentRequest{
+		"google pay": {GooglePay: &GooglePay{
+			Token:        "google-pay-dpan-4111",
+			Cryptogram:   util.Pointer("google-pay-cryptogram"),
+			Expiration:   "0642",
+			EciIndicator: "05",
+		}},
+		"apple pay": {ApplePay: &ApplePay{
+			Token:        "apple-pay-dpan-4111",
+			Cryptogram:   util.Pointer("apple-pay-cryptogram"),
+			Expiration:   "0642",
+			EciIndicator: "07",
+		}},
+	} {
+		buf.Reset()
+
+		payload.Amount = 4200
+		payload.Currency = CurrencyUAH
+
+		_, err = client.SyncPayment(context.Background(), payload)
+		require.NoError(t, err, name)
+
+		output := buf.String()
+
+		assert.NotContains(t, output, "dpan-4111", name)
+		assert.NotContains(t, output, "pay-cryptogram", name)
+		assert.Contains(t, output, `\"eciIndicator\"`, name)
+	}
+}
+
+func TestLogging_StreamStatement(t *testing.T) {
+	received := make(chan struct{})
+	streamed := make(chan bool, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/statement", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"list": [{"invoiceId": "2205175v4MfatvmUL2oR", "amount": 4200, "ccy": 980},`)
+		w.(http.Flusher).Flush()
+
+		// the rest of the list is written once the client decoded the first statement
+		select {
+		case <-received:
+			streamed <- true
+		case <-time.After(time.Second):
+			streamed <- false
+		}
+
+		_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3", "amount": 100, "ccy": 980}]}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL, Logger: logger}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	var count int
+
+	err = client.StreamStatement(context.Background(), GetStatementRequest{From: time.Unix(1722470400, 0)},
+		func(Statement) error {
+			if count++; count == 1 {
+				close(received)
+			}
+
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.True(t, <-streamed, "the logger must not read the streamed body ahead of the client")
+
+	records := decodeLogRecords(t, buf)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, OperationGetStatement, records[0]["operation"])
+	assert.NotContains(t, records[0], "response_body")
+}
+
+func TestLogging_ErrCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"errCode": "NOT_FOUND","errText": "invoice not found"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL, Logger: logger}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	_, err = client.GetInvoiceStatus(context.Background(), GetInvoiceStatusRequest{InvoiceID: "p2_9ZgpZVsl3"})
+
+	assert.True(t, IsInvoiceNotFound(err), "the error body must still be decoded")
+
+	var reqErr *RequestError
+
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, "invoice not found", reqErr.Message)
+
+	records := decodeLogRecords(t, buf)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, "NOT_FOUND", records[0]["err_code"])
+	assert.NotContains(t, records[0], "response_body", "bodies are logged at debug level only")
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := map[string]struct {
+		Body     string
+		Expected string
+	}{
+		"empty": {
+			Body:     "",
+			Expected: "",
+		},
+		"card": {
+			Body:     `{"amount":4200,"cardData":{"pan":"4444111122223333","exp":"0642","cvv":"123","cavv":"AAAA","tavv":"BBBB"}}`,
+			Expected: `{"amount":4200,"cardData":{"cavv":"***","cvv":"***","exp":"0642","pan":"***","tavv":"***"}}`,
+		},
+		"wallet list": {
+			Body:     `{"wallet":[{"cardToken":"67XZtXdR4NpKU3","maskedPan":"444403******1902"}]}`,
+			Expected: `{"wallet":[{"cardToken":"***","maskedPan":"444403******1902"}]}`,
+		},
+		"null value": {
+			Body:     `{"cvv":null}`,
+			Expected: `{"cvv":null}`,
+		},
+		"not json": {
+			Body:     `cvv=123`,
+			Expected: `<non-json body, 7 bytes>`,
+		},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, val.Expected, redactBody([]byte(val.Body)))
+		})
+	}
+}
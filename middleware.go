@@ -0,0 +1,141 @@
+package monoacquiring
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	OperationCreateInvoice        = "CreateInvoice"
+	OperationGetInvoiceStatus     = "GetInvoiceStatus"
+	OperationCancelInvoice        = "CancelInvoice"
+	OperationRemoveInvoice        = "RemoveInvoice"
+	OperationGetPublicKey         = "GetPublicKey"
+	OperationGetEmployeeList      = "GetEmployeeList"
+	OperationGetMerchantDetails   = "GetMerchantDetails"
+	OperationGetQRList            = "GetQRList"
+	OperationGetWalletCardList    = "GetWalletCardList"
+	OperationGetSplitReceiverList = "GetSplitReceiverList"
+	OperationRemoveWalletCard     = "RemoveWalletCard"
+	OperationGetQRDetails         = "GetQRDetails"
+	OperationQrResetAmount        = "QrResetAmount"
+	OperationGetSubMerchantList   = "GetSubMerchantList"
+	OperationGetReceipt           = "GetReceipt"
+	OperationGetFiscalChecks      = "GetFiscalChecks"
+	OperationGetStatement         = "GetStatement"
+	OperationFinalizeHold         = "FinalizeHold"
+	OperationSyncPayment          = "SyncPayment"
+	OperationTokenPayment         = "TokenPayment"
+	OperationDirectPayment        = "DirectPayment"
+
+	DefaultRequestIDHeader = "X-Request-Id"
+)
+
+var pathToOperation = map[string]string{
+	invoiceCreatePath:        OperationCreateInvoice,
+	invoiceStatusPath:        OperationGetInvoiceStatus,
+	invoiceCancelPath:        OperationCancelInvoice,
+	invoiceRemovePath:        OperationRemoveInvoice,
+	getPublicKeyPath:         OperationGetPublicKey,
+	getEmployeeListPath:      OperationGetEmployeeList,
+	getMerchantDetailsPath:   OperationGetMerchantDetails,
+	getQRListPath:            OperationGetQRList,
+	getWalletCardListPath:    OperationGetWalletCardList,
+	getSplitReceiverListPath: OperationGetSplitReceiverList,
+	removeWalletCardPath:     OperationRemoveWalletCard,
+	getQrDetailsPath:         OperationGetQRDetails,
+	qrResetAmountPath:        OperationQrResetAmount,
+	getSubMerchantListPath:   OperationGetSubMerchantList,
+	getReceiptPath:           OperationGetReceipt,
+	geFiscalChecksPath:       OperationGetFiscalChecks,
+	getStatementPath:         OperationGetStatement,
+	finalizeHoldPath:         OperationFinalizeHold,
+	syncPaymentPath:          OperationSyncPayment,
+	tokenPaymentPath:         OperationTokenPayment,
+	directPaymentPath:        OperationDirectPayment,
+}
+
+func isOperation(name string) bool {
+	for _, operation := range pathToOperation {
+		if operation == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func operationName(req *http.Request) string {
+	if operation, ok := pathToOperation[req.URL.Path]; ok {
+		return operation
+	}
+
+	return req.URL.Path
+}
+
+// RoundTripper sends a single attempt of an API operation, the operation is one of the Operation* constants.
+type RoundTripper interface {
+	RoundTrip(operation string, req *http.Request) (*http.Response, error)
+}
+
+type RoundTripperFunc func(operation string, req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(operation string, req *http.Request) (*http.Response, error) {
+	return f(operation, req)
+}
+
+// Middleware wraps every attempt of a request, including retries. Middlewares passed in Config
+// are applied in order, the first one is the outermost.
+type Middleware func(next RoundTripper) RoundTripper
+
+func chainMiddlewares(transport RoundTripper, middlewares []Middleware) RoundTripper {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+
+	return transport
+}
+
+// HeaderMiddleware sets the headers on every request, replacing the values set by the client.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+			for key, values := range header {
+				req.Header.Del(key)
+
+				for _, value := range values {
+					req.Header.Add(key, value)
+				}
+			}
+
+			return next.RoundTrip(operation, req)
+		})
+	}
+}
+
+// RequestIDMiddleware adds a random request id to the header, DefaultRequestIDHeader when empty.
+// Retries of the same request keep the id of the first attempt.
+func RequestIDMiddleware(header string) Middleware {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return func(next RoundTripper) RoundTripper {
+		return RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+			if req.Header.Get(header) == "" {
+				req.Header.Set(header, newRequestID())
+			}
+
+			return next.RoundTrip(operation, req)
+		})
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,144 @@
+package monoacquiring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_Order(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/create", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var calls []string
+
+	record := func(name string) Middleware {
+		return func(next RoundTripper) RoundTripper {
+			return RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+operation)
+
+				return next.RoundTrip(operation, req)
+			})
+		}
+	}
+
+	client, err := NewClient(Config{
+		APIKey:      "test",
+		BaseURL:     srv.URL,
+		Middlewares: []Middleware{record("first"), record("second")},
+	}, srv.Client(), nil)
+
+	assert.NoError(t, err)
+
+	_, err = client.CreateInvoice(context.Background(), InvoiceCreateRequest{Amount: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first CreateInvoice", "second CreateInvoice"}, calls)
+}
+
+func TestMiddleware_Retry(t *testing.T) {
+	var requestIDs []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/status", func(w http.ResponseWriter, req *http.Request) {
+		requestIDs = append(requestIDs, req.Header.Get(DefaultRequestIDHeader))
+
+		if len(requestIDs) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3", "status": "success"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var operations []string
+
+	client, err := NewClient(Config{
+		APIKey:  "test",
+		BaseURL: srv.URL,
+		Retry:   &RetryPolicy{InitialBackoff: time.Millisecond},
+		Middlewares: []Middleware{
+			RequestIDMiddleware(""),
+			func(next RoundTripper) RoundTripper {
+				return RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+					operations = append(operations, operation)
+
+					return next.RoundTrip(operation, req)
+				})
+			},
+		},
+	}, srv.Client(), nil)
+
+	assert.NoError(t, err)
+
+	_, err = client.GetInvoiceStatus(context.Background(), GetInvoiceStatusRequest{InvoiceID: "p2_9ZgpZVsl3"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{OperationGetInvoiceStatus, OperationGetInvoiceStatus}, operations)
+	assert.Len(t, requestIDs, 2)
+	assert.Len(t, requestIDs[0], 32)
+	assert.Equal(t, requestIDs[0], requestIDs[1])
+}
+
+func TestHeaderMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "custom-token", req.Header.Get("X-Token"))
+		assert.Equal(t, []string{"a", "b"}, req.Header.Values("Traceparent"))
+		assert.Equal(t, "golang", req.Header.Get("X-Cms"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(Config{
+		APIKey:  "test",
+		BaseURL: srv.URL,
+		Middlewares: []Middleware{
+			HeaderMiddleware(http.Header{"X-Token": {"custom-token"}, "Traceparent": {"a", "b"}}),
+		},
+	}, srv.Client(), nil)
+
+	assert.NoError(t, err)
+
+	_, err = client.GetMerchantDetails(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestOperationName(t *testing.T) {
+	tests := map[string]string{
+		invoiceCreatePath:      OperationCreateInvoice,
+		getStatementPath:       OperationGetStatement,
+		removeWalletCardPath:   OperationRemoveWalletCard,
+		getWalletCardListPath:  OperationGetWalletCardList,
+		"/api/merchant/custom": "/api/merchant/custom",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+
+			assert.Equal(t, expected, operationName(req))
+		})
+	}
+}
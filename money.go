@@ -0,0 +1,301 @@
+package monoacquiring
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrAmountOverflow   = errors.New("amount overflow")
+	ErrInvalidAmount    = errors.New("invalid amount")
+)
+
+// Currency is an ISO 4217 numeric currency code as used in the ccy fields.
+type Currency int
+
+const (
+	CurrencyUAH Currency = 980
+	CurrencyUSD Currency = 840
+	CurrencyEUR Currency = 978
+	CurrencyGBP Currency = 826
+	CurrencyPLN Currency = 985
+	CurrencyCHF Currency = 756
+	CurrencyCZK Currency = 203
+	CurrencyJPY Currency = 392
+	CurrencyKRW Currency = 410
+	CurrencyBHD Currency = 48
+	CurrencyKWD Currency = 414
+)
+
+const defaultCurrencyExponent = 2
+
+type currencyInfo struct {
+	code     string
+	exponent int
+}
+
+var currencies = map[Currency]currencyInfo{
+	CurrencyUAH: {code: "UAH", exponent: 2},
+	CurrencyUSD: {code: "USD", exponent: 2},
+	CurrencyEUR: {code: "EUR", exponent: 2},
+	CurrencyGBP: {code: "GBP", exponent: 2},
+	CurrencyPLN: {code: "PLN", exponent: 2},
+	CurrencyCHF: {code: "CHF", exponent: 2},
+	CurrencyCZK: {code: "CZK", exponent: 2},
+	CurrencyJPY: {code: "JPY", exponent: 0},
+	CurrencyKRW: {code: "KRW", exponent: 0},
+	CurrencyBHD: {code: "BHD", exponent: 3},
+	CurrencyKWD: {code: "KWD", exponent: 3},
+}
+
+// ParseCurrency accepts an alphabetic ("UAH") or numeric ("980") ISO 4217 code.
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	if numeric, err := strconv.Atoi(code); err == nil && numeric > 0 {
+		return Currency(numeric), nil
+	}
+
+	for currency, info := range currencies {
+		if info.code == code {
+			return currency, nil
+		}
+	}
+
+	return 0, errors.Errorf("unknown currency %q", code)
+}
+
+// Code returns the alphabetic code, or the numeric one for currencies the SDK does not know.
+func (c Currency) Code() string {
+	if info, ok := currencies[c]; ok {
+		return info.code
+	}
+
+	return strconv.Itoa(int(c))
+}
+
+// Exponent returns the number of minor unit digits, 2 for currencies the SDK does not know.
+func (c Currency) Exponent() int {
+	if info, ok := currencies[c]; ok {
+		return info.exponent
+	}
+
+	return defaultCurrencyExponent
+}
+
+func (c Currency) String() string {
+	return c.Code()
+}
+
+// Money is an amount in minor units (kopiykas for UAH) of the currency.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "42", "42.5" or "-0.05" in major units of the currency.
+func ParseMoney(value string, currency Currency) (Money, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	if negative || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	exponent := currency.Exponent()
+
+	if whole == "" || len(fraction) > exponent || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, errors.Wrapf(ErrInvalidAmount, "%q for %s", value, currency.Code())
+	}
+
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, errors.Wrapf(ErrAmountOverflow, "%q for %s", value, currency.Code())
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Decimal formats the amount in major units, e.g. "42.00".
+func (m Money) Decimal() string {
+	exponent := m.Currency.Exponent()
+
+	amount := strconv.FormatInt(m.Amount, 10)
+	sign := ""
+
+	if m.Amount < 0 {
+		sign, amount = "-", amount[1:]
+	}
+
+	if exponent == 0 {
+		return sign + amount
+	}
+
+	if len(amount) <= exponent {
+		amount = strings.Repeat("0", exponent-len(amount)+1) + amount
+	}
+
+	point := len(amount) - exponent
+
+	return sign + amount[:point] + "." + amount[point:]
+}
+
+// String formats the money as "42.00 UAH".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency.Code()
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, errors.Wrapf(ErrCurrencyMismatch, "%s + %s", m.Currency.Code(), other.Currency.Code())
+	}
+
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, errors.WithStack(ErrAmountOverflow)
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, errors.WithStack(ErrAmountOverflow)
+	}
+
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+func (m Money) Mul(factor int64) (Money, error) {
+	if m.Amount == 0 || factor == 0 {
+		return Money{Currency: m.Currency}, nil
+	}
+
+	result := m.Amount * factor
+
+	if result/factor != m.Amount || (m.Amount == -1 && factor == math.MinInt64) ||
+		(factor == -1 && m.Amount == math.MinInt64) {
+		return Money{}, errors.WithStack(ErrAmountOverflow)
+	}
+
+	return Money{Amount: result, Currency: m.Currency}, nil
+}
+
+// Cmp returns -1, 0 or 1 when m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, errors.Wrapf(ErrCurrencyMismatch, "%s and %s", m.Currency.Code(), other.Currency.Code())
+	}
+
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (r *InvoiceCreateRequest) SetMoney(m Money) {
+	r.Amount = m.Amount
+	r.Currency = &m.Currency
+}
+
+func (r *SyncPaymentRequest) SetMoney(m Money) {
+	r.Amount = m.Amount
+	r.Currency = m.Currency
+}
+
+func (r *TokenPaymentRequest) SetMoney(m Money) {
+	r.Amount = m.Amount
+	r.Currency = m.Currency
+}
+
+func (r *DirectPaymentRequest) SetMoney(m Money) {
+	r.Amount = m.Amount
+	r.Currency = &m.Currency
+}
+
+func (r *GetInvoiceStatusResponse) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+// FinalMoney returns the final amount, which is set only once the invoice is paid.
+func (r *GetInvoiceStatusResponse) FinalMoney() (Money, bool) {
+	if r.FinalAmount == nil {
+		return Money{}, false
+	}
+
+	return NewMoney(*r.FinalAmount, r.Currency), true
+}
+
+func (r *SyncPaymentResponse) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+// FinalMoney returns the final amount, which is set only once the payment is done.
+func (r *SyncPaymentResponse) FinalMoney() (Money, bool) {
+	if r.FinalAmount == nil {
+		return Money{}, false
+	}
+
+	return NewMoney(*r.FinalAmount, r.Currency), true
+}
+
+func (r *TokenPaymentResponse) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+func (r *DirectPaymentResponse) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+func (r *GetQrDetailsResponse) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+func (cli CancelListItem) Money() Money {
+	return NewMoney(cli.Amount, cli.Currency)
+}
+
+func (s Statement) Money() Money {
+	return NewMoney(s.Amount, s.Currency)
+}
+
+func (sc StatementCancel) Money() Money {
+	return NewMoney(sc.Amount, sc.Currency)
+}
@@ -0,0 +1,159 @@
+package monoacquiring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrency(t *testing.T) {
+	assert.Equal(t, "UAH", CurrencyUAH.Code())
+	assert.Equal(t, 2, CurrencyUAH.Exponent())
+	assert.Equal(t, 0, CurrencyJPY.Exponent())
+	assert.Equal(t, 3, CurrencyKWD.Exponent())
+
+	unknown := Currency(999)
+	assert.Equal(t, "999", unknown.String())
+	assert.Equal(t, 2, unknown.Exponent())
+
+	for code, expected := range map[string]Currency{
+		"UAH":  CurrencyUAH,
+		"eur":  CurrencyEUR,
+		"980":  CurrencyUAH,
+		" 840": CurrencyUSD,
+	} {
+		t.Run(code, func(t *testing.T) {
+			currency, err := ParseCurrency(code)
+			require.NoError(t, err)
+			assert.Equal(t, expected, currency)
+		})
+	}
+
+	_, err := ParseCurrency("XXX")
+	assert.Error(t, err)
+}
+
+func TestMoney_String(t *testing.T) {
+	for name, val := range map[string]struct {
+		Expected string
+		Money    Money
+	}{
+		"uah":          {Money: NewMoney(4200, CurrencyUAH), Expected: "42.00 UAH"},
+		"kopiykas":     {Money: NewMoney(5, CurrencyUAH), Expected: "0.05 UAH"},
+		"negative":     {Money: NewMoney(-150, CurrencyUSD), Expected: "-1.50 USD"},
+		"zero":         {Money: NewMoney(0, CurrencyEUR), Expected: "0.00 EUR"},
+		"no decimals":  {Money: NewMoney(4200, CurrencyJPY), Expected: "4200 JPY"},
+		"three digits": {Money: NewMoney(1005, CurrencyKWD), Expected: "1.005 KWD"},
+		"min int":      {Money: NewMoney(math.MinInt64, CurrencyUAH), Expected: "-92233720368547758.08 UAH"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, val.Expected, val.Money.String())
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	for name, val := range map[string]struct {
+		Value    string
+		Expected Money
+		Currency Currency
+	}{
+		"whole":        {Value: "42", Currency: CurrencyUAH, Expected: NewMoney(4200, CurrencyUAH)},
+		"decimals":     {Value: "42.5", Currency: CurrencyUAH, Expected: NewMoney(4250, CurrencyUAH)},
+		"negative":     {Value: "-0.05", Currency: CurrencyUAH, Expected: NewMoney(-5, CurrencyUAH)},
+		"no decimals":  {Value: "4200", Currency: CurrencyJPY, Expected: NewMoney(4200, CurrencyJPY)},
+		"three digits": {Value: "1.005", Currency: CurrencyKWD, Expected: NewMoney(1005, CurrencyKWD)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, err := ParseMoney(val.Value, val.Currency)
+			require.NoError(t, err)
+			assert.Equal(t, val.Expected, m)
+		})
+	}
+
+	for name, val := range map[string]struct {
+		Err      error
+		Value    string
+		Currency Currency
+	}{
+		"double sign":   {Value: "-+5", Currency: CurrencyUAH, Err: ErrInvalidAmount},
+		"empty":         {Value: "", Currency: CurrencyUAH, Err: ErrInvalidAmount},
+		"too precise":   {Value: "1.005", Currency: CurrencyUAH, Err: ErrInvalidAmount},
+		"yen decimals":  {Value: "1.5", Currency: CurrencyJPY, Err: ErrInvalidAmount},
+		"not a number":  {Value: "1,50", Currency: CurrencyUAH, Err: ErrInvalidAmount},
+		"leading point": {Value: ".5", Currency: CurrencyUAH, Err: ErrInvalidAmount},
+		"overflow":      {Value: "92233720368547758.08", Currency: CurrencyUAH, Err: ErrAmountOverflow},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseMoney(val.Value, val.Currency)
+			assert.ErrorIs(t, err, val.Err)
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := NewMoney(4200, CurrencyUAH)
+	b := NewMoney(800, CurrencyUAH)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(5000, CurrencyUAH), sum)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.True(t, diff.IsNegative())
+	assert.Equal(t, "-34.00 UAH", diff.String())
+
+	product, err := b.Mul(3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2400), product.Amount)
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	_, err = a.Add(NewMoney(100, CurrencyUSD))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = a.Cmp(NewMoney(100, CurrencyUSD))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewMoney(math.MaxInt64, CurrencyUAH).Add(NewMoney(1, CurrencyUAH))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = NewMoney(math.MinInt64, CurrencyUAH).Sub(NewMoney(1, CurrencyUAH))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = NewMoney(math.MaxInt64/2+1, CurrencyUAH).Mul(2)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = NewMoney(math.MinInt64, CurrencyUAH).Mul(-1)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	assert.True(t, NewMoney(0, CurrencyUAH).IsZero())
+}
+
+func TestMoney_Models(t *testing.T) {
+	m := NewMoney(4200, CurrencyEUR)
+
+	var invoice InvoiceCreateRequest
+
+	invoice.SetMoney(m)
+	assert.Equal(t, int64(4200), invoice.Amount)
+	assert.Equal(t, CurrencyEUR, *invoice.Currency)
+
+	status := GetInvoiceStatusResponse{Amount: 4200, Currency: CurrencyUAH}
+
+	_, ok := status.FinalMoney()
+	assert.False(t, ok)
+
+	status.FinalAmount = new(int64)
+	*status.FinalAmount = 4000
+
+	final, ok := status.FinalMoney()
+	assert.True(t, ok)
+	assert.Equal(t, "40.00 UAH", final.String())
+	assert.Equal(t, "42.00 UAH", status.Money().String())
+}
@@ -0,0 +1,298 @@
+package monotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var payload monoacquiring.InvoiceCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, err.Error())
+
+		return
+	}
+
+	if payload.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, "invalid 'amount'")
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+
+	now := s.now()
+	inv := &invoice{
+		id:          fmt.Sprintf("%smonotest%04d", now.Format("060102"), s.seq),
+		status:      monoacquiring.InvoiceStatusCreated,
+		paymentType: util.Ternary(payload.PaymentType == "", monoacquiring.PaymentTypeDebit, payload.PaymentType),
+		amount:      payload.Amount,
+		currency:    util.Ternary(payload.Currency == nil, monoacquiring.CurrencyUAH, util.PointerValue(payload.Currency)),
+		webHookURL:  util.PointerValue(payload.WebHookURL),
+		created:     now,
+		modified:    now,
+	}
+
+	if payload.MerchantPaymentInfo != nil {
+		inv.reference = payload.MerchantPaymentInfo.Reference
+		inv.destination = payload.MerchantPaymentInfo.Destination
+	}
+
+	s.invoices[inv.id] = inv
+	s.order = append(s.order, inv.id)
+
+	writeJSON(w, monoacquiring.InvoiceCreateResponse{
+		InvoiceID: inv.id,
+		PageURL:   s.srv.URL + "/pay/" + inv.id,
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	invoiceID := r.URL.Query().Get("invoiceId")
+	if invoiceID == "" {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, "empty 'invoiceId'")
+
+		return
+	}
+
+	status, err := s.Invoice(invoiceID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, monoacquiring.ErrCodeNotFound, err.Error())
+
+		return
+	}
+
+	writeJSON(w, status)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	var payload monoacquiring.CancelInvoiceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, err.Error())
+
+		return
+	}
+
+	var item monoacquiring.CancelListItem
+
+	webhook, err := s.update(payload.InvoiceID, func(inv *invoice) error {
+		if inv.status != monoacquiring.InvoiceStatusSuccess && inv.status != monoacquiring.InvoiceStatusHold {
+			return errors.Errorf("invoice can not be cancelled in status %s", inv.status)
+		}
+
+		remaining := inv.amount - inv.refunded()
+		if inv.finalAmount != nil {
+			remaining = *inv.finalAmount - inv.refunded()
+		}
+
+		amount := util.Ternary(payload.Amount == nil, remaining, util.PointerValue(payload.Amount))
+
+		if amount <= 0 || amount > remaining {
+			return errors.New("invalid 'amount'")
+		}
+
+		date := monoacquiring.NewTimestamp(s.now())
+		item = monoacquiring.CancelListItem{
+			Status:            monoacquiring.CancelListItemStatus("success"),
+			CreatedDate:       date,
+			ModifiedDate:      date,
+			ExternalReference: util.PointerValue(payload.ExternalReference),
+			Amount:            amount,
+			Currency:          inv.currency,
+		}
+
+		inv.cancelList = append(inv.cancelList, item)
+
+		if amount == remaining {
+			inv.status = monoacquiring.InvoiceStatusReversed
+		}
+
+		return nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+
+		return
+	}
+
+	_ = s.notify(r.Context(), webhook)
+
+	writeJSON(w, monoacquiring.CancelInvoiceResponse{
+		Status:       item.Status,
+		CreatedDate:  item.CreatedDate,
+		ModifiedDate: item.ModifiedDate,
+	})
+}
+
+func (s *Server) handleRemove(w http.ResponseWriter, r *http.Request) {
+	var payload monoacquiring.RemoveInvoiceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, err.Error())
+
+		return
+	}
+
+	webhook, err := s.update(payload.InvoiceID, func(inv *invoice) error {
+		if inv.status != monoacquiring.InvoiceStatusCreated {
+			return errors.Errorf("invoice can not be removed in status %s", inv.status)
+		}
+
+		inv.status = monoacquiring.InvoiceStatusExpired
+
+		return nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+
+		return
+	}
+
+	_ = s.notify(r.Context(), webhook)
+
+	writeJSON(w, struct{}{})
+}
+
+func (s *Server) handleFinalize(w http.ResponseWriter, r *http.Request) {
+	var payload monoacquiring.FinalizeHoldRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, err.Error())
+
+		return
+	}
+
+	webhook, err := s.update(payload.InvoiceID, func(inv *invoice) error {
+		if inv.status != monoacquiring.InvoiceStatusHold {
+			return errors.New("invoice is not in hold")
+		}
+
+		amount := util.Ternary(payload.Amount == nil, inv.amount, util.PointerValue(payload.Amount))
+
+		if amount <= 0 || amount > inv.amount {
+			return errors.New("invalid 'amount'")
+		}
+
+		inv.status = monoacquiring.InvoiceStatusSuccess
+		inv.finalAmount = &amount
+
+		return nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+
+		return
+	}
+
+	_ = s.notify(r.Context(), webhook)
+
+	writeJSON(w, monoacquiring.FinalizeHoldResponse{Status: "success"})
+}
+
+func (s *Server) handleStatement(w http.ResponseWriter, r *http.Request) {
+	from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, "invalid 'from'")
+
+		return
+	}
+
+	var to *int64
+
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, "invalid 'to'")
+
+			return
+		}
+
+		to = &parsed
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if to == nil {
+		to = util.Pointer(s.now().Unix())
+	}
+
+	result := monoacquiring.GetStatementResponse{List: []monoacquiring.Statement{}}
+
+	for _, id := range s.order {
+		inv := s.invoices[id]
+
+		if inv.created.Unix() < from || inv.created.Unix() > *to {
+			continue
+		}
+
+		if statement, ok := inv.statement(); ok {
+			result.List = append(result.List, statement)
+		}
+	}
+
+	writeJSON(w, result)
+}
+
+func (s *Server) handlePublicKey(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, monoacquiring.GetPublicKeyResponse{Key: s.publicKey})
+}
+
+func (inv *invoice) statement() (monoacquiring.Statement, bool) {
+	var status monoacquiring.InvoiceStatus
+
+	switch inv.status {
+	case monoacquiring.InvoiceStatusHold:
+		status = monoacquiring.InvoiceStatusHold
+	case monoacquiring.InvoiceStatusSuccess, monoacquiring.InvoiceStatusReversed:
+		status = monoacquiring.InvoiceStatusSuccess
+	case monoacquiring.InvoiceStatusProcessing:
+		status = monoacquiring.InvoiceStatusProcessing
+	default:
+		return monoacquiring.Statement{}, false
+	}
+
+	statement := monoacquiring.Statement{
+		InvoiceID:     inv.id,
+		Status:        monoacquiring.StatementStatus(status),
+		MaskedPan:     "444403******1902",
+		Date:          monoacquiring.NewTimestamp(inv.modified),
+		PaymentScheme: monoacquiring.StatementPaymentScheme("full"),
+		Reference:     inv.reference,
+		Destination:   inv.destination,
+		Amount:        util.PointerValue(inv.finalAmount),
+		Currency:      inv.currency,
+	}
+
+	for _, item := range inv.cancelList {
+		statement.CancelList = append(statement.CancelList, monoacquiring.StatementCancel{
+			MaskedPan: statement.MaskedPan,
+			Date:      item.CreatedDate,
+			Amount:    item.Amount,
+			Currency:  item.Currency,
+		})
+	}
+
+	return statement, true
+}
+
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrInvoiceNotFound) {
+		writeError(w, http.StatusNotFound, monoacquiring.ErrCodeNotFound, err.Error())
+
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, monoacquiring.ErrCodeBadRequest, err.Error())
+}
@@ -0,0 +1,382 @@
+// Package monotest runs an in-process fake of the monobank acquiring API for integration tests.
+//
+// The fake keeps invoices in memory: CreateInvoice creates them, Pay and Fail simulate the customer,
+// CancelInvoice, FinalizeHold and RemoveInvoice change their state and GetStatement lists the paid ones.
+// Every state change is sent to the invoice webHookUrl signed with the server key, which is served by
+// GetPublicKey, so webhook.SignatureVerifier accepts it.
+package monotest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+)
+
+const DefaultAPIKey = "monotest-token"
+
+var ErrInvoiceNotFound = errors.New("invoice not found")
+
+type invoice struct {
+	created       time.Time
+	modified      time.Time
+	webHookURL    string
+	reference     *string
+	destination   *string
+	failureReason *string
+	errCode       *string
+	finalAmount   *int64
+	id            string
+	status        monoacquiring.InvoiceStatus
+	paymentType   string
+	cancelList    []monoacquiring.CancelListItem
+	amount        int64
+	currency      monoacquiring.Currency
+}
+
+type Server struct {
+	now         func() time.Time
+	srv         *httptest.Server
+	key         *ecdsa.PrivateKey
+	invoices    map[string]*invoice
+	httpClient  *http.Client
+	apiKey      string
+	publicKey   string
+	order       []string
+	webhookErrs []error
+	seq         int
+	mu          sync.Mutex
+}
+
+// NewServer starts a fake accepting DefaultAPIKey, it must be closed when the test is done.
+func NewServer() (*Server, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	s := &Server{
+		now:        time.Now,
+		key:        key,
+		invoices:   make(map[string]*invoice),
+		httpClient: http.DefaultClient,
+		apiKey:     DefaultAPIKey,
+		publicKey:  base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/merchant/invoice/create", s.handleCreate)
+	mux.HandleFunc("GET /api/merchant/invoice/status", s.handleStatus)
+	mux.HandleFunc("POST /api/merchant/invoice/cancel", s.handleCancel)
+	mux.HandleFunc("POST /api/merchant/invoice/remove", s.handleRemove)
+	mux.HandleFunc("POST /api/merchant/invoice/finalize", s.handleFinalize)
+	mux.HandleFunc("GET /api/merchant/statement", s.handleStatement)
+	mux.HandleFunc("GET /api/merchant/pubkey", s.handlePublicKey)
+
+	s.srv = httptest.NewServer(s.authenticate(mux))
+
+	return s, nil
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Config returns a client configuration pointing to the fake.
+func (s *Server) Config() monoacquiring.Config {
+	return monoacquiring.Config{APIKey: s.apiKey, BaseURL: s.srv.URL}
+}
+
+// PublicKey returns the key webhooks are signed with, in the format accepted by webhook.NewSignatureVerifier.
+func (s *Server) PublicKey() string {
+	return s.publicKey
+}
+
+// SetNow replaces the clock used for invoice dates.
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// SetWebhookClient replaces the client used to deliver webhooks, http.DefaultClient by default.
+func (s *Server) SetWebhookClient(client *http.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.httpClient = client
+}
+
+// Sign returns the X-Sign value for the body.
+func (s *Server) Sign(body []byte) (string, error) {
+	hash := sha256.Sum256(body)
+
+	sign, err := ecdsa.SignASN1(rand.Reader, s.key, hash[:])
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(sign), nil
+}
+
+// Invoice returns the current state of the invoice as GetInvoiceStatus would.
+func (s *Server) Invoice(invoiceID string) (*monoacquiring.GetInvoiceStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inv, ok := s.invoices[invoiceID]
+	if !ok {
+		return nil, ErrInvoiceNotFound
+	}
+
+	status := inv.response()
+
+	return &status, nil
+}
+
+// Pay simulates a successful payment: a debit invoice becomes success, a hold invoice becomes hold.
+func (s *Server) Pay(ctx context.Context, invoiceID string) error {
+	return s.transition(ctx, invoiceID, func(inv *invoice) error {
+		if inv.status != monoacquiring.InvoiceStatusCreated && inv.status != monoacquiring.InvoiceStatusProcessing {
+			return errors.Errorf("invoice %s can not be paid in status %s", inv.id, inv.status)
+		}
+
+		inv.status = monoacquiring.InvoiceStatusSuccess
+		if inv.paymentType == monoacquiring.PaymentTypeHold {
+			inv.status = monoacquiring.InvoiceStatusHold
+		}
+
+		amount := inv.amount
+		inv.finalAmount = &amount
+
+		return nil
+	})
+}
+
+// Fail simulates a declined payment.
+func (s *Server) Fail(ctx context.Context, invoiceID, errCode, failureReason string) error {
+	return s.transition(ctx, invoiceID, func(inv *invoice) error {
+		if inv.status != monoacquiring.InvoiceStatusCreated && inv.status != monoacquiring.InvoiceStatusProcessing {
+			return errors.Errorf("invoice %s can not fail in status %s", inv.id, inv.status)
+		}
+
+		inv.status = monoacquiring.InvoiceStatusFailure
+		inv.errCode = &errCode
+		inv.failureReason = &failureReason
+
+		return nil
+	})
+}
+
+// SetStatus moves the invoice to any status, without checking the transition.
+func (s *Server) SetStatus(ctx context.Context, invoiceID string, status monoacquiring.InvoiceStatus) error {
+	return s.transition(ctx, invoiceID, func(inv *invoice) error {
+		inv.status = status
+
+		return nil
+	})
+}
+
+// SendWebhook delivers the current state of the invoice to its webHookUrl.
+func (s *Server) SendWebhook(ctx context.Context, invoiceID string) error {
+	s.mu.Lock()
+
+	inv, ok := s.invoices[invoiceID]
+	if !ok {
+		s.mu.Unlock()
+
+		return ErrInvoiceNotFound
+	}
+
+	webhook := &pendingWebhook{client: s.httpClient, url: inv.webHookURL, status: inv.response()}
+
+	s.mu.Unlock()
+
+	if webhook.url == "" {
+		return errors.Errorf("invoice %s has no webhook url", invoiceID)
+	}
+
+	return s.notify(ctx, webhook)
+}
+
+type pendingWebhook struct {
+	client *http.Client
+	url    string
+	status monoacquiring.GetInvoiceStatusResponse
+}
+
+// update changes the invoice under the lock and returns the webhook to deliver after it.
+func (s *Server) update(invoiceID string, fn func(inv *invoice) error) (*pendingWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inv, ok := s.invoices[invoiceID]
+	if !ok {
+		return nil, ErrInvoiceNotFound
+	}
+
+	if err := fn(inv); err != nil {
+		return nil, err
+	}
+
+	inv.modified = s.now()
+
+	return &pendingWebhook{client: s.httpClient, url: inv.webHookURL, status: inv.response()}, nil
+}
+
+func (s *Server) transition(ctx context.Context, invoiceID string, fn func(inv *invoice) error) error {
+	webhook, err := s.update(invoiceID, fn)
+	if err != nil {
+		return err
+	}
+
+	return s.notify(ctx, webhook)
+}
+
+// notify delivers the webhook, a failed delivery is also kept for WebhookErrors.
+func (s *Server) notify(ctx context.Context, webhook *pendingWebhook) error {
+	if webhook.url == "" {
+		return nil
+	}
+
+	err := s.deliver(ctx, webhook.client, webhook.url, webhook.status)
+	if err != nil {
+		s.mu.Lock()
+		s.webhookErrs = append(s.webhookErrs, err)
+		s.mu.Unlock()
+	}
+
+	return err
+}
+
+// WebhookErrors returns the failed deliveries of webhooks sent by the API handlers.
+func (s *Server) WebhookErrors() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]error(nil), s.webhookErrs...)
+}
+
+func (s *Server) deliver(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	status monoacquiring.GetInvoiceStatusResponse,
+) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	sign, err := s.Sign(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sign", sign)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("webhook for invoice %s was rejected with status %d", status.InvoiceID, res.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != s.apiKey {
+			writeError(w, http.StatusForbidden, monoacquiring.ErrCodeForbidden, "invalid 'X-Token'")
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (inv *invoice) response() monoacquiring.GetInvoiceStatusResponse {
+	var finalAmount *int64
+
+	if inv.finalAmount != nil {
+		amount := *inv.finalAmount
+		finalAmount = &amount
+	}
+
+	return monoacquiring.GetInvoiceStatusResponse{
+		InvoiceID:     inv.id,
+		Status:        inv.status,
+		Amount:        inv.amount,
+		Currency:      inv.currency,
+		FinalAmount:   finalAmount,
+		CreatedDate:   monoacquiring.NewTimestamp(inv.created),
+		ModifiedDate:  monoacquiring.NewTimestamp(inv.modified),
+		Reference:     inv.reference,
+		Destination:   inv.destination,
+		ErrCode:       inv.errCode,
+		FailureReason: inv.failureReason,
+		CancelList:    append([]monoacquiring.CancelListItem(nil), inv.cancelList...),
+	}
+}
+
+func (inv *invoice) refunded() int64 {
+	var total int64
+
+	for _, item := range inv.cancelList {
+		if !item.Status.IsFailure() {
+			total += item.Amount
+		}
+	}
+
+	return total
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, _ = fmt.Fprintf(w, `{"errCode":%q,"errText":%q}`, code, message)
+}
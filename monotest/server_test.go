@@ -0,0 +1,279 @@
+package monotest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) (*Server, *monoacquiring.Client) {
+	t.Helper()
+
+	srv, err := NewServer()
+	require.NoError(t, err)
+
+	t.Cleanup(srv.Close)
+
+	client, err := monoacquiring.NewClient(srv.Config(), nil, nil)
+	require.NoError(t, err)
+
+	return srv, client
+}
+
+func TestServer_InvoiceLifecycle(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{
+		Amount:              4200,
+		MerchantPaymentInfo: &monoacquiring.MerchantPaymentInfo{Reference: util.Pointer("order-1")},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.InvoiceID)
+	assert.Contains(t, created.PageURL, created.InvoiceID)
+
+	status, err := client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: created.InvoiceID})
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusCreated, status.Status)
+	assert.Equal(t, int64(4200), status.Amount)
+	assert.Equal(t, monoacquiring.CurrencyUAH, status.Currency)
+	assert.Equal(t, "order-1", util.PointerValue(status.Reference))
+
+	require.NoError(t, srv.Pay(ctx, created.InvoiceID))
+
+	status, err = client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: created.InvoiceID})
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusSuccess, status.Status)
+	assert.Equal(t, int64(4200), util.PointerValue(status.FinalAmount))
+
+	cancel, err := client.CancelInvoice(ctx, monoacquiring.CancelInvoiceRequest{
+		InvoiceID: created.InvoiceID,
+		Amount:    util.Pointer(int64(1000)),
+	})
+	require.NoError(t, err)
+	assert.True(t, cancel.Status.IsSuccess())
+
+	status, err = client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: created.InvoiceID})
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusSuccess, status.Status)
+	assert.Len(t, status.CancelList, 1)
+
+	_, err = client.CancelInvoice(ctx, monoacquiring.CancelInvoiceRequest{
+		InvoiceID: created.InvoiceID,
+		Amount:    util.Pointer(int64(5000)),
+	})
+	assert.ErrorIs(t, err, monoacquiring.ErrBadRequestHTTPStatus)
+
+	_, err = client.CancelInvoice(ctx, monoacquiring.CancelInvoiceRequest{InvoiceID: created.InvoiceID})
+	require.NoError(t, err)
+
+	status, err = client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: created.InvoiceID})
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusReversed, status.Status)
+	assert.Len(t, status.CancelList, 2)
+	assert.Equal(t, int64(3200), status.CancelList[1].Amount)
+}
+
+func TestServer_Hold(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{
+		Amount:      4200,
+		PaymentType: monoacquiring.PaymentTypeHold,
+	})
+	require.NoError(t, err)
+
+	_, err = client.FinalizeHold(ctx, monoacquiring.FinalizeHoldRequest{InvoiceID: created.InvoiceID})
+	assert.ErrorIs(t, err, monoacquiring.ErrBadRequestHTTPStatus, "not paid yet")
+
+	require.NoError(t, srv.Pay(ctx, created.InvoiceID))
+
+	status, err := srv.Invoice(created.InvoiceID)
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusHold, status.Status)
+
+	finalized, err := client.FinalizeHold(ctx, monoacquiring.FinalizeHoldRequest{
+		InvoiceID: created.InvoiceID,
+		Amount:    util.Pointer(int64(4000)),
+	})
+	require.NoError(t, err)
+	assert.True(t, finalized.Status.IsSuccess())
+
+	status, err = srv.Invoice(created.InvoiceID)
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusSuccess, status.Status)
+	assert.Equal(t, int64(4000), util.PointerValue(status.FinalAmount))
+}
+
+func TestServer_RemoveAndFail(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	first, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{Amount: 100})
+	require.NoError(t, err)
+
+	require.NoError(t, client.RemoveInvoice(ctx, monoacquiring.RemoveInvoiceRequest{InvoiceID: first.InvoiceID}))
+
+	status, err := srv.Invoice(first.InvoiceID)
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusExpired, status.Status)
+
+	second, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{Amount: 100})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.Fail(ctx, second.InvoiceID, "59", "Неправильний CVV код"))
+
+	status, err = client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: second.InvoiceID})
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusFailure, status.Status)
+	assert.True(t, status.FailureCode().IsInvalidCVV())
+
+	_, err = client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: "unknown"})
+	assert.True(t, monoacquiring.IsInvoiceNotFound(err))
+}
+
+func TestServer_Statement(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	now := time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)
+	srv.SetNow(func() time.Time { return now })
+
+	ids := make([]string, 3)
+
+	for i := range ids {
+		created, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{Amount: int64(100 * (i + 1))})
+		require.NoError(t, err)
+
+		ids[i] = created.InvoiceID
+		now = now.Add(time.Hour)
+	}
+
+	require.NoError(t, srv.Pay(ctx, ids[0]))
+	require.NoError(t, srv.Pay(ctx, ids[2]))
+
+	res, err := client.GetStatement(ctx, monoacquiring.GetStatementRequest{
+		From: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC),
+		To:   util.Pointer(time.Date(2025, 8, 12, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	require.Len(t, res.List, 2, "unpaid invoices are not in the statement")
+	assert.Equal(t, ids[0], res.List[0].InvoiceID)
+	assert.Equal(t, int64(100), res.List[0].Amount)
+	assert.Equal(t, ids[2], res.List[1].InvoiceID)
+
+	res, err = client.GetStatement(ctx, monoacquiring.GetStatementRequest{
+		From: time.Date(2025, 8, 11, 8, 0, 0, 0, time.UTC),
+		To:   util.Pointer(time.Date(2025, 8, 12, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	require.Len(t, res.List, 1)
+	assert.Equal(t, ids[2], res.List[0].InvoiceID)
+}
+
+func TestServer_StatementDefaultTo(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	now := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	srv.SetNow(func() time.Time { return now })
+
+	created, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{Amount: 100})
+	require.NoError(t, err)
+	require.NoError(t, srv.Pay(ctx, created.InvoiceID))
+
+	res, err := client.GetStatement(ctx, monoacquiring.GetStatementRequest{
+		From: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, res.List, 1, "the default 'to' is the time of the server")
+	assert.Equal(t, created.InvoiceID, res.List[0].InvoiceID)
+}
+
+func TestServer_Webhook(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	keyProvider := webhook.NewClientKeyProvider(client, 0)
+
+	var events []webhook.InvoiceEvent
+
+	handler, err := webhook.NewHandler(webhook.HandlerConfig{
+		Verifier: webhook.NewSignatureVerifierWithProvider(keyProvider),
+		OnEvent: func(_ context.Context, event webhook.InvoiceEvent) error {
+			events = append(events, event)
+
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	receiver := httptest.NewServer(handler)
+	defer receiver.Close()
+
+	created, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{
+		Amount:     4200,
+		WebHookURL: util.Pointer(receiver.URL + "/webhook"),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, srv.Pay(ctx, created.InvoiceID))
+
+	_, err = client.CancelInvoice(ctx, monoacquiring.CancelInvoiceRequest{InvoiceID: created.InvoiceID})
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, created.InvoiceID, events[0].InvoiceID)
+	assert.Equal(t, monoacquiring.InvoiceStatusSuccess, events[0].Status)
+	assert.Equal(t, monoacquiring.InvoiceStatusReversed, events[1].Status)
+	assert.Empty(t, srv.WebhookErrors())
+
+	require.NoError(t, srv.SendWebhook(ctx, created.InvoiceID))
+	assert.Len(t, events, 3)
+}
+
+func TestServer_WebhookRejected(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer receiver.Close()
+
+	created, err := client.CreateInvoice(ctx, monoacquiring.InvoiceCreateRequest{
+		Amount:     4200,
+		WebHookURL: util.Pointer(receiver.URL),
+	})
+	require.NoError(t, err)
+
+	assert.Error(t, srv.Pay(ctx, created.InvoiceID))
+	assert.Len(t, srv.WebhookErrors(), 1)
+
+	status, err := srv.Invoice(created.InvoiceID)
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusSuccess, status.Status, "the state changes even when the webhook is rejected")
+}
+
+func TestServer_Forbidden(t *testing.T) {
+	srv, err := NewServer()
+	require.NoError(t, err)
+
+	defer srv.Close()
+
+	client, err := monoacquiring.NewClient(monoacquiring.Config{APIKey: "wrong", BaseURL: srv.URL()}, nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.CreateInvoice(context.Background(), monoacquiring.InvoiceCreateRequest{Amount: 100})
+
+	assert.ErrorIs(t, err, monoacquiring.ErrForbiddenHTTPStatus)
+}
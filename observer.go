@@ -0,0 +1,24 @@
+package monoacquiring
+
+import (
+	"context"
+	"time"
+)
+
+// Observer receives one start and one end notification for every API operation.
+// The context returned by OperationStart is used for the request, so it can carry a span.
+type Observer interface {
+	OperationStart(ctx context.Context, operation string) context.Context
+	OperationEnd(ctx context.Context, operation string, result OperationResult)
+}
+
+type OperationResult struct {
+	// Err is the error returned to the caller.
+	Err error
+	// ErrCode is RequestError.Code when the API returned an error.
+	ErrCode  string
+	Duration time.Duration
+	// StatusCode is the status of the last attempt, zero when no response was received.
+	StatusCode int
+	Retries    int
+}
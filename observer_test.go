@@ -0,0 +1,102 @@
+package monoacquiring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type observerCtxKey struct{}
+
+type recordingObserver struct {
+	started []string
+	ended   []OperationResult
+}
+
+func (o *recordingObserver) OperationStart(ctx context.Context, operation string) context.Context {
+	o.started = append(o.started, operation)
+
+	return context.WithValue(ctx, observerCtxKey{}, operation)
+}
+
+func (o *recordingObserver) OperationEnd(_ context.Context, _ string, result OperationResult) {
+	o.ended = append(o.ended, result)
+}
+
+func TestObserver(t *testing.T) {
+	calls := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/status", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = fmt.Fprint(w, `{"errCode": "TOO_MANY_REQUESTS","errText": "too many requests"}`)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"errCode": "NOT_FOUND","errText": "invoice not found"}`)
+	})
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var (
+		observer   = &recordingObserver{}
+		ctxValues  []any
+		middleware = func(next RoundTripper) RoundTripper {
+			return RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+				ctxValues = append(ctxValues, req.Context().Value(observerCtxKey{}))
+
+				return next.RoundTrip(operation, req)
+			})
+		}
+	)
+
+	client, err := NewClient(Config{
+		APIKey:      "test",
+		BaseURL:     srv.URL,
+		Retry:       &RetryPolicy{InitialBackoff: time.Millisecond},
+		Observer:    observer,
+		Middlewares: []Middleware{middleware},
+	}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	_, err = client.GetInvoiceStatus(context.Background(), GetInvoiceStatusRequest{InvoiceID: "p2_9ZgpZVsl3"})
+	assert.Error(t, err)
+
+	_, err = client.GetMerchantDetails(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{OperationGetInvoiceStatus, OperationGetMerchantDetails}, observer.started)
+	assert.Equal(t, []any{OperationGetInvoiceStatus, OperationGetInvoiceStatus, OperationGetInvoiceStatus, OperationGetMerchantDetails}, ctxValues)
+	require.Len(t, observer.ended, 2)
+
+	failed := observer.ended[0]
+
+	assert.ErrorIs(t, failed.Err, ErrNotFoundHTTPStatus)
+	assert.Equal(t, ErrCodeNotFound, failed.ErrCode)
+	assert.Equal(t, http.StatusNotFound, failed.StatusCode)
+	assert.Equal(t, 2, failed.Retries)
+	assert.Positive(t, failed.Duration)
+
+	succeeded := observer.ended[1]
+
+	assert.NoError(t, succeeded.Err)
+	assert.Empty(t, succeeded.ErrCode)
+	assert.Equal(t, http.StatusOK, succeeded.StatusCode)
+	assert.Zero(t, succeeded.Retries)
+}
@@ -0,0 +1,101 @@
+package otelobserver
+
+import (
+	"context"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ScopeName = "git.kbyte.app/mono/sdk/mono-acquiring-go"
+
+	SpanNamePrefix = "monobank.acquiring."
+
+	DurationMetric = "monobank.acquiring.client.duration"
+	RetriesMetric  = "monobank.acquiring.client.retries"
+
+	OperationKey  = attribute.Key("monobank.acquiring.operation")
+	ErrCodeKey    = attribute.Key("monobank.acquiring.err_code")
+	RetriesKey    = attribute.Key("monobank.acquiring.retries")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// Observer turns client operations into client spans and duration and retry histograms.
+type Observer struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	retries  metric.Int64Histogram
+}
+
+var _ monoacquiring.Observer = (*Observer)(nil)
+
+func New(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Observer, error) {
+	meter := meterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram(
+		DurationMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of monobank acquiring operations including retries."),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	retries, err := meter.Int64Histogram(
+		RetriesMetric,
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("Number of retries of monobank acquiring operations."),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Observer{
+		tracer:   tracerProvider.Tracer(ScopeName),
+		duration: duration,
+		retries:  retries,
+	}, nil
+}
+
+func (o *Observer) OperationStart(ctx context.Context, operation string) context.Context {
+	ctx, _ = o.tracer.Start(
+		ctx,
+		SpanNamePrefix+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(OperationKey.String(operation)),
+	)
+
+	return ctx
+}
+
+func (o *Observer) OperationEnd(ctx context.Context, operation string, result monoacquiring.OperationResult) {
+	attrs := []attribute.KeyValue{OperationKey.String(operation)}
+
+	if result.StatusCode != 0 {
+		attrs = append(attrs, StatusCodeKey.Int(result.StatusCode))
+	}
+
+	if result.ErrCode != "" {
+		attrs = append(attrs, ErrCodeKey.String(result.ErrCode))
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(append(attrs, RetriesKey.Int(result.Retries))...)
+
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	}
+
+	span.End()
+
+	set := metric.WithAttributes(attrs...)
+
+	o.duration.Record(ctx, result.Duration.Seconds(), set)
+	o.retries.Record(ctx, int64(result.Retries), set)
+}
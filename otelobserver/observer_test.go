@@ -0,0 +1,129 @@
+package otelobserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestObserver(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"errCode": "NOT_FOUND","errText": "invoice not found"}`)
+	})
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	observer, err := New(tracerProvider, meterProvider)
+	require.NoError(t, err)
+
+	var spanContexts []trace.SpanContext
+
+	client, err := monoacquiring.NewClient(monoacquiring.Config{
+		APIKey:   "test",
+		BaseURL:  srv.URL,
+		Observer: observer,
+		Middlewares: []monoacquiring.Middleware{
+			func(next monoacquiring.RoundTripper) monoacquiring.RoundTripper {
+				return monoacquiring.RoundTripperFunc(func(operation string, req *http.Request) (*http.Response, error) {
+					spanContexts = append(spanContexts, trace.SpanContextFromContext(req.Context()))
+
+					return next.RoundTrip(operation, req)
+				})
+			},
+		},
+	}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = client.GetInvoiceStatus(ctx, monoacquiring.GetInvoiceStatusRequest{InvoiceID: "p2_9ZgpZVsl3"})
+	assert.Error(t, err)
+
+	_, err = client.GetMerchantDetails(ctx)
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+
+	require.Len(t, spans, 2)
+
+	failed := spans[0]
+
+	assert.Equal(t, "monobank.acquiring.GetInvoiceStatus", failed.Name)
+	assert.Equal(t, trace.SpanKindClient, failed.SpanKind)
+	assert.Equal(t, codes.Error, failed.Status.Code)
+	assert.Contains(t, failed.Attributes, StatusCodeKey.Int(http.StatusNotFound))
+	assert.Contains(t, failed.Attributes, ErrCodeKey.String("NOT_FOUND"))
+	assert.Contains(t, failed.Attributes, RetriesKey.Int(0))
+	assert.Len(t, failed.Events, 1, "the error is recorded")
+
+	succeeded := spans[1]
+
+	assert.Equal(t, "monobank.acquiring.GetMerchantDetails", succeeded.Name)
+	assert.Equal(t, codes.Unset, succeeded.Status.Code)
+	assert.Contains(t, succeeded.Attributes, StatusCodeKey.Int(http.StatusOK))
+
+	require.Len(t, spanContexts, 2)
+	assert.Equal(t, failed.SpanContext.SpanID(), spanContexts[0].SpanID(), "requests carry the operation span")
+	assert.Equal(t, succeeded.SpanContext.SpanID(), spanContexts[1].SpanID())
+
+	var metrics metricdata.ResourceMetrics
+
+	require.NoError(t, reader.Collect(ctx, &metrics))
+	require.Len(t, metrics.ScopeMetrics, 1)
+
+	histograms := make(map[string]metricdata.Histogram[float64])
+	retries := make(map[string]metricdata.Histogram[int64])
+
+	for _, m := range metrics.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Histogram[float64]:
+			histograms[m.Name] = data
+		case metricdata.Histogram[int64]:
+			retries[m.Name] = data
+		}
+	}
+
+	duration, ok := histograms[DurationMetric]
+
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 2)
+
+	for _, point := range duration.DataPoints {
+		assert.Equal(t, uint64(1), point.Count)
+
+		operation, ok := point.Attributes.Value(OperationKey)
+
+		assert.True(t, ok)
+		assert.Contains(t, []attribute.Value{
+			attribute.StringValue(monoacquiring.OperationGetInvoiceStatus),
+			attribute.StringValue(monoacquiring.OperationGetMerchantDetails),
+		}, operation)
+	}
+
+	assert.Contains(t, retries, RetriesMetric)
+}
@@ -16,10 +16,10 @@ type GetQrDetailsRequest struct {
 }
 
 type GetQrDetailsResponse struct {
-	ShortQrID string `json:"shortQrId"`
-	InvoiceID string `json:"invoiceId"`
-	Amount    int    `json:"amount"`
-	Currency  int    `json:"ccy"`
+	ShortQrID string   `json:"shortQrId"`
+	InvoiceID string   `json:"invoiceId"`
+	Amount    int64    `json:"amount"`
+	Currency  Currency `json:"ccy"`
 }
 
 func (c *Client) GetQRDetails(ctx context.Context, payload GetQrDetailsRequest) (*GetQrDetailsResponse, error) {
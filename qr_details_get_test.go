@@ -49,8 +49,8 @@ func TestGetQRDetails(t *testing.T) {
 	assert.NotNil(t, res)
 	assert.Equal(t, "OBJE", res.ShortQrID)
 	assert.Equal(t, "4EwIUTA12JIZ", res.InvoiceID)
-	assert.Equal(t, 4200, res.Amount)
-	assert.Equal(t, 980, res.Currency)
+	assert.Equal(t, int64(4200), res.Amount)
+	assert.Equal(t, "42.00 UAH", res.Money().String())
 }
 
 func TestGetQRDetails_Validation(t *testing.T) {
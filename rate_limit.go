@@ -0,0 +1,142 @@
+package monoacquiring
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrRateLimitDeadline = errors.New("rate limit delay exceeds context deadline")
+
+// RateLimit is a token bucket: Burst requests can be sent at once, then one more every Interval.
+type RateLimit struct {
+	Interval time.Duration `validate:"required,gt=0"`
+	Burst    int           `validate:"min=0"`
+}
+
+type tokenBucket struct {
+	updated  time.Time
+	now      func() time.Time
+	interval time.Duration
+	tokens   float64
+	burst    float64
+	mu       sync.Mutex
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(max(limit.Burst, 1))
+
+	return &tokenBucket{
+		interval: limit.Interval,
+		tokens:   burst,
+		burst:    burst,
+		now:      time.Now,
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait before using it.
+func (b *tokenBucket) reserve(deadline time.Time, hasDeadline bool) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	if !b.updated.IsZero() {
+		b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.updated))/float64(b.interval))
+	}
+
+	b.updated = now
+
+	var delay time.Duration
+
+	if b.tokens < 1 {
+		delay = time.Duration((1 - b.tokens) * float64(b.interval))
+	}
+
+	if hasDeadline && now.Add(delay).After(deadline) {
+		return 0, false
+	}
+
+	b.tokens--
+
+	return delay, true
+}
+
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	deadline, hasDeadline := ctx.Deadline()
+
+	delay, ok := b.reserve(deadline, hasDeadline)
+	if !ok {
+		return errors.WithStack(ErrRateLimitDeadline)
+	}
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+
+		return errors.WithStack(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+type rateLimiter struct {
+	common     *tokenBucket
+	operations map[string]*tokenBucket
+}
+
+func newRateLimiter(common *RateLimit, operations map[string]RateLimit) (*rateLimiter, error) {
+	if common == nil && len(operations) == 0 {
+		return nil, nil
+	}
+
+	limiter := rateLimiter{operations: make(map[string]*tokenBucket, len(operations))}
+
+	if common != nil {
+		limiter.common = newTokenBucket(*common)
+	}
+
+	for operation, limit := range operations {
+		if !isOperation(operation) {
+			return nil, errors.Errorf("rate limit of unknown operation %q", operation)
+		}
+
+		limiter.operations[operation] = newTokenBucket(limit)
+	}
+
+	return &limiter, nil
+}
+
+func (l *rateLimiter) wait(req *http.Request) error {
+	if l == nil {
+		return nil
+	}
+
+	bucket, ok := l.operations[operationName(req)]
+	if !ok {
+		bucket = l.common
+	}
+
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.wait(req.Context())
+}
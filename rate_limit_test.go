@@ -0,0 +1,139 @@
+package monoacquiring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	now := time.Now()
+
+	bucket := newTokenBucket(RateLimit{Interval: time.Second, Burst: 2})
+	bucket.now = func() time.Time { return now }
+
+	for range 2 {
+		delay, ok := bucket.reserve(time.Time{}, false)
+
+		assert.True(t, ok)
+		assert.Zero(t, delay)
+	}
+
+	delay, ok := bucket.reserve(time.Time{}, false)
+
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	now = now.Add(1500 * time.Millisecond)
+
+	delay, ok = bucket.reserve(time.Time{}, false)
+
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	_, ok = bucket.reserve(now.Add(time.Second), true)
+
+	assert.False(t, ok, "the delay does not fit the deadline")
+
+	now = now.Add(time.Hour)
+
+	delay, ok = bucket.reserve(time.Time{}, false)
+
+	assert.True(t, ok)
+	assert.Zero(t, delay)
+}
+
+func TestRateLimit_Path(t *testing.T) {
+	var statementCalls, detailsCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/statement", func(w http.ResponseWriter, _ *http.Request) {
+		statementCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"list": []}`)
+	})
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		detailsCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(Config{
+		APIKey:  "test",
+		BaseURL: srv.URL,
+		RateLimits: map[string]RateLimit{
+			OperationGetStatement: {Interval: time.Hour, Burst: 1},
+		},
+	}, srv.Client(), nil)
+
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = client.GetStatement(ctx, GetStatementRequest{From: time.Unix(1755692087, 0)})
+
+	assert.NoError(t, err)
+
+	_, err = client.GetStatement(ctx, GetStatementRequest{From: time.Unix(1755692087, 0)})
+
+	assert.ErrorIs(t, err, ErrRateLimitDeadline)
+	assert.Equal(t, int32(1), statementCalls.Load())
+
+	for range 3 {
+		_, err = client.GetMerchantDetails(ctx)
+
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(3), detailsCalls.Load())
+}
+
+func TestRateLimit_Wait(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(Config{
+		APIKey:    "test",
+		BaseURL:   srv.URL,
+		RateLimit: &RateLimit{Interval: 50 * time.Millisecond, Burst: 1},
+	}, srv.Client(), nil)
+
+	assert.NoError(t, err)
+
+	start := time.Now()
+
+	for range 3 {
+		_, err = client.GetMerchantDetails(context.Background())
+
+		assert.NoError(t, err)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRateLimit_Validation(t *testing.T) {
+	for name, limits := range map[string]map[string]RateLimit{
+		"no interval":       {OperationGetStatement: {}},
+		"unknown operation": {getStatementPath: {Interval: time.Second}},
+	} {
+		_, err := NewClient(Config{APIKey: "test", BaseURL: DefaultBaseURL, RateLimits: limits}, nil, nil)
+
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,265 @@
+// Package reconcile matches the money movement of GetStatement against the payments expected by an order ledger.
+package reconcile
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+)
+
+// Expected is a payment of the ledger, it is matched by InvoiceID and, when no statement has it, by Reference.
+type Expected struct {
+	// OrderID identifies the payment in the ledger, it is not used for matching.
+	OrderID   string
+	InvoiceID string
+	// Reference is the merchantPaymInfo.reference the invoice was created with.
+	Reference string
+	Amount    monoacquiring.Money
+}
+
+func (e Expected) key() string {
+	if e.InvoiceID != "" {
+		return e.InvoiceID
+	}
+
+	return e.Reference
+}
+
+// Match is an expected payment together with its statement.
+type Match struct {
+	Expected  Expected
+	Statement monoacquiring.Statement
+	// Refunded is the total of the statement cancel list.
+	Refunded monoacquiring.Money
+}
+
+// Net returns the paid amount minus the refunded one.
+func (m Match) Net() monoacquiring.Money {
+	return monoacquiring.NewMoney(m.Statement.Amount-m.Refunded.Amount, m.Statement.Currency)
+}
+
+// Report sorts the payments into disjoint buckets: a matched statement goes to Failed or Pending by its status,
+// to AmountMismatch when its amount or currency differs, to Refunded or PartiallyRefunded when it has a cancel
+// list and to Matched otherwise.
+type Report struct {
+	Matched           []Match
+	AmountMismatch    []Match
+	PartiallyRefunded []Match
+	Refunded          []Match
+	// Failed are statements in the failure status, no money was received.
+	Failed []Match
+	// Pending are statements in the hold or processing status, the money is not received yet.
+	Pending []Match
+	// Missing are expected payments without a statement, including a second payment expected for the same invoice.
+	Missing []Expected
+	// Unexpected are statements no payment was expected for.
+	Unexpected []monoacquiring.Statement
+	Summary    Summary
+}
+
+type Summary struct {
+	ExpectedTotal map[monoacquiring.Currency]monoacquiring.Money
+	// ReceivedTotal sums the statements of the received payments, the failed and pending ones are left out.
+	ReceivedTotal     map[monoacquiring.Currency]monoacquiring.Money
+	RefundedTotal     map[monoacquiring.Currency]monoacquiring.Money
+	ExpectedCount     int
+	StatementCount    int
+	Matched           int
+	AmountMismatch    int
+	PartiallyRefunded int
+	Refunded          int
+	Failed            int
+	Pending           int
+	Missing           int
+	Unexpected        int
+}
+
+// Slice adapts a slice of expected payments to the iterator accepted by Reconcile.
+func Slice(expected []Expected) iter.Seq2[Expected, error] {
+	return func(yield func(Expected, error) bool) {
+		for _, e := range expected {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Reconcile matches the statements against the expected payments, an error of the iterator stops it.
+func Reconcile(statements []monoacquiring.Statement, expected iter.Seq2[Expected, error]) (*Report, error) {
+	byInvoice := make(map[string]int, len(statements))
+	byReference := make(map[string]int, len(statements))
+
+	for i, statement := range statements {
+		byInvoice[statement.InvoiceID] = i
+
+		if statement.Reference != nil && *statement.Reference != "" {
+			byReference[*statement.Reference] = i
+		}
+	}
+
+	report := &Report{}
+	matched := make([]bool, len(statements))
+
+	for e, err := range expected {
+		if err != nil {
+			return nil, err
+		}
+
+		if e.key() == "" {
+			return nil, errors.Errorf("expected payment %q has neither invoice id nor reference", e.OrderID)
+		}
+
+		i, ok := byInvoice[e.InvoiceID]
+		if !ok && e.Reference != "" {
+			i, ok = byReference[e.Reference]
+		}
+
+		if !ok || matched[i] {
+			report.Missing = append(report.Missing, e)
+
+			continue
+		}
+
+		matched[i] = true
+
+		report.add(Match{Statement: statements[i], Expected: e, Refunded: refunded(statements[i])})
+	}
+
+	for i, statement := range statements {
+		if !matched[i] {
+			report.Unexpected = append(report.Unexpected, statement)
+		}
+	}
+
+	report.Summary = report.summarize(len(statements))
+
+	return report, nil
+}
+
+func refunded(statement monoacquiring.Statement) monoacquiring.Money {
+	total := monoacquiring.NewMoney(0, statement.Currency)
+
+	for _, item := range statement.CancelList {
+		total.Amount += item.Amount
+	}
+
+	return total
+}
+
+// isReceived reports whether the money of the statement was received.
+func isReceived(statement monoacquiring.Statement) bool {
+	return !statement.Status.IsFailure() && !statement.Status.IsHold() && !statement.Status.IsProcessing()
+}
+
+func (r *Report) add(m Match) {
+	switch {
+	case m.Statement.Status.IsFailure():
+		r.Failed = append(r.Failed, m)
+	case !isReceived(m.Statement):
+		r.Pending = append(r.Pending, m)
+	case m.Statement.Currency != m.Expected.Amount.Currency || m.Statement.Amount != m.Expected.Amount.Amount:
+		r.AmountMismatch = append(r.AmountMismatch, m)
+	case m.Refunded.Amount >= m.Statement.Amount && m.Refunded.Amount > 0:
+		r.Refunded = append(r.Refunded, m)
+	case m.Refunded.Amount > 0:
+		r.PartiallyRefunded = append(r.PartiallyRefunded, m)
+	default:
+		r.Matched = append(r.Matched, m)
+	}
+}
+
+func (r *Report) summarize(statementCount int) Summary {
+	s := Summary{
+		ExpectedTotal:     make(map[monoacquiring.Currency]monoacquiring.Money),
+		ReceivedTotal:     make(map[monoacquiring.Currency]monoacquiring.Money),
+		RefundedTotal:     make(map[monoacquiring.Currency]monoacquiring.Money),
+		StatementCount:    statementCount,
+		Matched:           len(r.Matched),
+		AmountMismatch:    len(r.AmountMismatch),
+		PartiallyRefunded: len(r.PartiallyRefunded),
+		Refunded:          len(r.Refunded),
+		Failed:            len(r.Failed),
+		Pending:           len(r.Pending),
+		Missing:           len(r.Missing),
+		Unexpected:        len(r.Unexpected),
+	}
+
+	for _, bucket := range [][]Match{r.Matched, r.AmountMismatch, r.PartiallyRefunded, r.Refunded} {
+		for _, m := range bucket {
+			s.ExpectedCount++
+
+			addTotal(s.ExpectedTotal, m.Expected.Amount)
+			addTotal(s.ReceivedTotal, m.Statement.Money())
+			addTotal(s.RefundedTotal, m.Refunded)
+		}
+	}
+
+	for _, bucket := range [][]Match{r.Failed, r.Pending} {
+		for _, m := range bucket {
+			s.ExpectedCount++
+
+			addTotal(s.ExpectedTotal, m.Expected.Amount)
+		}
+	}
+
+	for _, e := range r.Missing {
+		s.ExpectedCount++
+
+		addTotal(s.ExpectedTotal, e.Amount)
+	}
+
+	for _, statement := range r.Unexpected {
+		if !isReceived(statement) {
+			continue
+		}
+
+		addTotal(s.ReceivedTotal, statement.Money())
+		addTotal(s.RefundedTotal, refunded(statement))
+	}
+
+	return s
+}
+
+func addTotal(totals map[monoacquiring.Currency]monoacquiring.Money, m monoacquiring.Money) {
+	total := totals[m.Currency]
+	total.Currency = m.Currency
+	total.Amount += m.Amount
+
+	totals[m.Currency] = total
+}
+
+// String formats the summary as a plain text report.
+func (s Summary) String() string {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "expected: %d, statements: %d\n", s.ExpectedCount, s.StatementCount)
+	_, _ = fmt.Fprintf(&b, "matched: %d, amount mismatch: %d, partially refunded: %d, refunded: %d\n",
+		s.Matched, s.AmountMismatch, s.PartiallyRefunded, s.Refunded)
+	_, _ = fmt.Fprintf(&b, "failed: %d, pending: %d, missing: %d, unexpected: %d\n",
+		s.Failed, s.Pending, s.Missing, s.Unexpected)
+
+	for _, total := range []struct {
+		totals map[monoacquiring.Currency]monoacquiring.Money
+		name   string
+	}{
+		{name: "expected", totals: s.ExpectedTotal},
+		{name: "received", totals: s.ReceivedTotal},
+		{name: "refunded", totals: s.RefundedTotal},
+	} {
+		amounts := make([]string, 0, len(total.totals))
+
+		for _, currency := range slices.Sorted(maps.Keys(total.totals)) {
+			amounts = append(amounts, total.totals[currency].String())
+		}
+
+		_, _ = fmt.Fprintf(&b, "%s total: %s\n", total.name, strings.Join(amounts, ", "))
+	}
+
+	return b.String()
+}
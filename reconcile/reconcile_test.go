@@ -0,0 +1,126 @@
+package reconcile
+
+import (
+	"testing"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uah(amount int64) monoacquiring.Money {
+	return monoacquiring.NewMoney(amount, monoacquiring.CurrencyUAH)
+}
+
+func statement(invoiceID, reference string, amount int64, cancels ...int64) monoacquiring.Statement {
+	s := monoacquiring.Statement{
+		InvoiceID: invoiceID,
+		Status:    monoacquiring.StatementStatus("success"),
+		Amount:    amount,
+		Currency:  monoacquiring.CurrencyUAH,
+	}
+
+	if reference != "" {
+		s.Reference = util.Pointer(reference)
+	}
+
+	for _, cancel := range cancels {
+		s.CancelList = append(s.CancelList, monoacquiring.StatementCancel{
+			Amount:   cancel,
+			Currency: monoacquiring.CurrencyUAH,
+		})
+	}
+
+	return s
+}
+
+func TestReconcile(t *testing.T) {
+	statements := []monoacquiring.Statement{
+		statement("inv-1", "order-1", 4200),
+		statement("inv-2", "order-2", 1000),
+		statement("inv-3", "order-3", 5000, 1000),
+		statement("inv-4", "order-4", 3000, 1000, 2000),
+		statement("inv-5", "", 700),
+		statement("inv-6", "order-6", 100),
+		statement("inv-8", "order-8", 800),
+		statement("inv-9", "order-9", 900),
+		statement("inv-10", "order-10", 50),
+	}
+
+	statements[6].Status = monoacquiring.StatementStatus("failure")
+	statements[7].Status = monoacquiring.StatementStatus("hold")
+	statements[8].Status = monoacquiring.StatementStatus("failure")
+
+	report, err := Reconcile(statements, Slice([]Expected{
+		{OrderID: "1", InvoiceID: "inv-1", Amount: uah(4200)},
+		{OrderID: "2", Reference: "order-2", Amount: uah(1200)},
+		{OrderID: "3", InvoiceID: "inv-3", Amount: uah(5000)},
+		{OrderID: "4", InvoiceID: "unknown", Reference: "order-4", Amount: uah(3000)},
+		{OrderID: "6", Reference: "order-6", Amount: monoacquiring.NewMoney(100, monoacquiring.CurrencyUSD)},
+		{OrderID: "7", Reference: "order-7", Amount: uah(900)},
+		{OrderID: "1-again", InvoiceID: "inv-1", Amount: uah(4200)},
+		{OrderID: "8", InvoiceID: "inv-8", Amount: uah(800)},
+		{OrderID: "9", InvoiceID: "inv-9", Amount: uah(900)},
+	}))
+	require.NoError(t, err)
+
+	orderIDs := func(matches []Match) []string {
+		ids := make([]string, 0, len(matches))
+
+		for _, m := range matches {
+			ids = append(ids, m.Expected.OrderID)
+		}
+
+		return ids
+	}
+
+	assert.Equal(t, []string{"1"}, orderIDs(report.Matched))
+	assert.Equal(t, []string{"2", "6"}, orderIDs(report.AmountMismatch), "amount and currency")
+	assert.Equal(t, []string{"3"}, orderIDs(report.PartiallyRefunded))
+	assert.Equal(t, []string{"4"}, orderIDs(report.Refunded), "matched by reference")
+	assert.Equal(t, []string{"8"}, orderIDs(report.Failed), "a failure with the expected amount is not matched")
+	assert.Equal(t, []string{"9"}, orderIDs(report.Pending), "a hold is not matched")
+
+	require.Len(t, report.PartiallyRefunded, 1)
+	assert.Equal(t, uah(1000), report.PartiallyRefunded[0].Refunded)
+	assert.Equal(t, uah(4000), report.PartiallyRefunded[0].Net())
+
+	require.Len(t, report.Missing, 2)
+	assert.Equal(t, "7", report.Missing[0].OrderID)
+	assert.Equal(t, "1-again", report.Missing[1].OrderID, "a statement is matched once")
+
+	require.Len(t, report.Unexpected, 2)
+	assert.Equal(t, "inv-5", report.Unexpected[0].InvoiceID)
+	assert.Equal(t, "inv-10", report.Unexpected[1].InvoiceID)
+
+	summary := report.Summary
+
+	assert.Equal(t, 9, summary.ExpectedCount)
+	assert.Equal(t, 9, summary.StatementCount)
+	assert.Equal(t, uah(4200+1200+5000+3000+900+4200+800+900), summary.ExpectedTotal[monoacquiring.CurrencyUAH])
+	assert.Equal(t, uah(4200+1000+5000+3000+700+100), summary.ReceivedTotal[monoacquiring.CurrencyUAH],
+		"failed and pending statements are not received")
+	assert.Equal(t, uah(4000), summary.RefundedTotal[monoacquiring.CurrencyUAH])
+
+	assert.Equal(t, `expected: 9, statements: 9
+matched: 1, amount mismatch: 2, partially refunded: 1, refunded: 1
+failed: 1, pending: 1, missing: 2, unexpected: 2
+expected total: 1.00 USD, 202.00 UAH
+received total: 140.00 UAH
+refunded total: 40.00 UAH
+`, summary.String())
+}
+
+func TestReconcile_Error(t *testing.T) {
+	failure := errors.New("ledger is unavailable")
+
+	_, err := Reconcile(nil, func(yield func(Expected, error) bool) {
+		yield(Expected{}, failure)
+	})
+	assert.ErrorIs(t, err, failure)
+
+	_, err = Reconcile(nil, Slice([]Expected{{OrderID: "1", Amount: uah(100)}}))
+	assert.Error(t, err, "no key")
+}
@@ -0,0 +1,90 @@
+package monoacquiring
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotRefundable        = errors.New("invoice is not refundable")
+	ErrRefundExceedsBalance = errors.New("refund exceeds the refundable amount")
+)
+
+// Refundable returns the paid amount, FinalAmount once it is set, minus the successful and processing cancels.
+// It is zero unless the invoice is in the success or hold status.
+func (r *GetInvoiceStatusResponse) Refundable() Money {
+	if !r.Status.IsSuccess() && !r.Status.IsHold() {
+		return NewMoney(0, r.Currency)
+	}
+
+	paid, ok := r.FinalMoney()
+	if !ok {
+		paid = r.Money()
+	}
+
+	for _, item := range r.CancelList {
+		if item.Status.IsSuccess() || item.Status.IsProcessing() {
+			paid.Amount -= item.Amount
+		}
+	}
+
+	paid.Amount = max(paid.Amount, 0)
+
+	return paid
+}
+
+// Refund cancels the amount of the invoice, the whole refundable amount when it is zero. The refundable amount is
+// checked against the current status of the invoice before CancelInvoice is sent with a generated external
+// reference, which identifies the returned cancel entry in the cancel list of the invoice.
+func (c *Client) Refund(
+	ctx context.Context,
+	invoiceID string,
+	amount int64,
+	items []CancelInvoiceItem,
+) (*CancelListItem, error) {
+	status, err := c.GetInvoiceStatus(ctx, GetInvoiceStatusRequest{InvoiceID: invoiceID})
+	if err != nil {
+		return nil, err
+	}
+
+	if !status.Status.IsSuccess() && !status.Status.IsHold() {
+		return nil, errors.Wrapf(ErrNotRefundable, "invoice %s in status %s", invoiceID, status.Status)
+	}
+
+	refundable := status.Refundable()
+
+	if amount == 0 {
+		amount = refundable.Amount
+	}
+
+	if amount < 0 {
+		return nil, errors.Errorf("refund amount %d is negative", amount)
+	}
+
+	if amount > refundable.Amount || refundable.IsZero() {
+		return nil, errors.Wrapf(ErrRefundExceedsBalance, "refund of %s, refundable %s",
+			NewMoney(amount, refundable.Currency), refundable)
+	}
+
+	extRef := NewIdempotencyKey()
+
+	res, err := c.CancelInvoice(ctx, CancelInvoiceRequest{
+		InvoiceID:         invoiceID,
+		ExternalReference: &extRef,
+		Amount:            &amount,
+		Items:             items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CancelListItem{
+		Status:            res.Status,
+		CreatedDate:       res.CreatedDate,
+		ModifiedDate:      res.ModifiedDate,
+		ExternalReference: extRef,
+		Amount:            amount,
+		Currency:          refundable.Currency,
+	}, nil
+}
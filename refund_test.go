@@ -0,0 +1,104 @@
+package monoacquiring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefund(t *testing.T) {
+	var cancels []CancelInvoiceRequest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{
+  "invoiceId": "p2_9ZgpZVsl3",
+  "status": "success",
+  "amount": 10000,
+  "finalAmount": 9000,
+  "ccy": 980,
+  "cancelList": [
+    {"status": "success", "amount": 2000, "ccy": 980},
+    {"status": "processing", "amount": 1000, "ccy": 980},
+    {"status": "failure", "amount": 5000, "ccy": 980}
+  ]
+}`)
+	})
+	mux.HandleFunc("/api/merchant/invoice/cancel", func(w http.ResponseWriter, r *http.Request) {
+		var payload CancelInvoiceRequest
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		cancels = append(cancels, payload)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"status": "processing", "createdDate": "2025-07-17T12:00:00+03:00"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	_, err = client.Refund(context.Background(), "p2_9ZgpZVsl3", 6001, nil)
+	require.ErrorIs(t, err, ErrRefundExceedsBalance, "9000 paid, 3000 refunded")
+	assert.Empty(t, cancels, "rejected locally")
+
+	item, err := client.Refund(context.Background(), "p2_9ZgpZVsl3", 1500, []CancelInvoiceItem{
+		{Name: "Хліб", Code: "bread", Qty: 1, Sum: 1500},
+	})
+	require.NoError(t, err)
+	assert.True(t, item.Status.IsProcessing())
+	assert.Equal(t, "15.00 UAH", item.Money().String())
+	assert.Len(t, item.ExternalReference, 32)
+
+	item, err = client.Refund(context.Background(), "p2_9ZgpZVsl3", 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6000), item.Amount, "the whole refundable amount")
+
+	require.Len(t, cancels, 2)
+	assert.Equal(t, int64(1500), *cancels[0].Amount)
+	assert.Equal(t, item.ExternalReference, *cancels[1].ExternalReference)
+	assert.NotEqual(t, *cancels[0].ExternalReference, *cancels[1].ExternalReference)
+}
+
+func TestGetInvoiceStatusResponse_Refundable(t *testing.T) {
+	for name, val := range map[string]struct {
+		Response GetInvoiceStatusResponse
+		Expected int64
+	}{
+		"hold": {
+			Response: GetInvoiceStatusResponse{Status: InvoiceStatusHold, Amount: 500},
+			Expected: 500,
+		},
+		"created": {
+			Response: GetInvoiceStatusResponse{Status: InvoiceStatusCreated, Amount: 500},
+		},
+		"reversed": {
+			Response: GetInvoiceStatusResponse{
+				Status:     InvoiceStatusReversed,
+				Amount:     500,
+				CancelList: []CancelListItem{{Status: CancelListItemStatus("success"), Amount: 500}},
+			},
+		},
+		"refunded in full": {
+			Response: GetInvoiceStatusResponse{
+				Status:     InvoiceStatusSuccess,
+				Amount:     500,
+				CancelList: []CancelListItem{{Status: CancelListItemStatus("success"), Amount: 500}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, val.Expected, val.Response.Refundable().Amount)
+		})
+	}
+}
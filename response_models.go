@@ -2,7 +2,7 @@ package monoacquiring
 
 type TipsInfo struct {
 	EmployeeID string `json:"employeeId"`
-	Amount     int    `json:"amount"`
+	Amount     int64  `json:"amount"`
 }
 
 const (
@@ -133,13 +133,13 @@ func (cli CancelListItemStatus) IsFailure() bool {
 
 type CancelListItem struct {
 	Status            CancelListItemStatus `json:"status"`
-	CreatedDate       string               `json:"createdDate"`
-	ModifiedDate      string               `json:"modifiedDate"`
+	CreatedDate       Timestamp            `json:"createdDate"`
+	ModifiedDate      Timestamp            `json:"modifiedDate"`
 	ApprovalCode      string               `json:"approvalCode"`
 	RRN               string               `json:"rrn"`
 	ExternalReference string               `json:"extRef"`
 	Amount            int64                `json:"amount"`
-	Currency          int                  `json:"ccy"`
+	Currency          Currency             `json:"ccy"`
 }
 
 const (
@@ -292,18 +292,18 @@ func (ssp StatementPaymentScheme) IsFull() bool {
 }
 
 type StatementCancel struct {
-	ApprovalCode *string `json:"approvalCode,omitempty"`
-	RRN          *string `json:"rrn,omitempty"`
-	MaskedPan    string  `json:"maskedPan"`
-	Date         string  `json:"date"`
-	Amount       int64   `json:"amount"`
-	Currency     int     `json:"ccy"`
+	ApprovalCode *string   `json:"approvalCode,omitempty"`
+	RRN          *string   `json:"rrn,omitempty"`
+	MaskedPan    string    `json:"maskedPan"`
+	Date         Timestamp `json:"date"`
+	Amount       int64     `json:"amount"`
+	Currency     Currency  `json:"ccy"`
 }
 
 type Statement struct {
 	InvoiceID     string                 `json:"invoiceId"`
 	MaskedPan     string                 `json:"maskedPan"`
-	Date          string                 `json:"date"`
+	Date          Timestamp              `json:"date"`
 	Status        StatementStatus        `json:"status"`
 	PaymentScheme StatementPaymentScheme `json:"paymentScheme"`
 	ApprovalCode  *string                `json:"approvalCode,omitempty"`
@@ -314,7 +314,7 @@ type Statement struct {
 	ProfitAmount  *int64                 `json:"profitAmount,omitempty"`
 	CancelList    []StatementCancel      `json:"cancelList,omitempty"`
 	Amount        int64                  `json:"amount"`
-	Currency      int                    `json:"ccy"`
+	Currency      Currency               `json:"ccy"`
 }
 
 const (
@@ -341,84 +341,11 @@ const (
 	SyncPaymentStatusExpired    = "expired"
 )
 
-type SyncPaymentStatus string
+// SyncPaymentStatus is kept for compatibility, it is the InvoiceStatus.
+type SyncPaymentStatus = InvoiceStatus
 
-func (sps SyncPaymentStatus) String() string {
-	return string(sps)
-}
-
-func (sps SyncPaymentStatus) IsCreated() bool {
-	return sps.String() == SyncPaymentStatusCreated
-}
-
-func (sps SyncPaymentStatus) IsProcessing() bool {
-	return sps.String() == SyncPaymentStatusProcessing
-}
-
-func (sps SyncPaymentStatus) IsHold() bool {
-	return sps.String() == SyncPaymentStatusHold
-}
-
-func (sps SyncPaymentStatus) IsSuccess() bool {
-	return sps.String() == SyncPaymentStatusSuccess
-}
-
-func (sps SyncPaymentStatus) IsFailure() bool {
-	return sps.String() == SyncPaymentStatusFailure
-}
-
-func (sps SyncPaymentStatus) IsReversed() bool {
-	return sps.String() == SyncPaymentStatusReversed
-}
-
-func (sps SyncPaymentStatus) IsExpired() bool {
-	return sps.String() == SyncPaymentStatusExpired
-}
-
-const (
-	tokenPaymentStatusProcessing = "processing"
-	tokenPaymentStatusSuccess    = "success"
-	tokenPaymentStatusFailure    = "failure"
-)
-
-type TokenPaymentStatus string
+// TokenPaymentStatus is kept for compatibility, it is the InvoiceStatus.
+type TokenPaymentStatus = InvoiceStatus
 
-func (tps TokenPaymentStatus) String() string {
-	return string(tps)
-}
-
-func (tps TokenPaymentStatus) IsProcessing() bool {
-	return tps.String() == tokenPaymentStatusProcessing
-}
-
-func (tps TokenPaymentStatus) IsSuccess() bool {
-	return tps.String() == tokenPaymentStatusSuccess
-}
-
-func (tps TokenPaymentStatus) IsFailure() bool {
-	return tps.String() == tokenPaymentStatusFailure
-}
-
-const (
-	directPaymentStatusProcessing = "processing"
-	directPaymentStatusSuccess    = "success"
-	directPaymentStatusFailure    = "failure"
-)
-
-type DirectPaymentStatus string
-
-func (dps DirectPaymentStatus) String() string {
-	return string(dps)
-}
-
-func (dps DirectPaymentStatus) IsProcessing() bool {
-	return dps.String() == directPaymentStatusProcessing
-}
-
-func (dps DirectPaymentStatus) IsSuccess() bool {
-	return dps.String() == directPaymentStatusSuccess
-}
-
-func (dps DirectPaymentStatus) IsFailure() bool {
-	return dps.String() == directPaymentStatusFailure
-}
+// DirectPaymentStatus is kept for compatibility, it is the InvoiceStatus.
+type DirectPaymentStatus = InvoiceStatus
@@ -0,0 +1,205 @@
+package monoacquiring
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = 200 * time.Millisecond
+	DefaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy enables retries of failed requests. Only GET requests are retried, other methods are retried
+// only when the context was marked with WithRetrySafe. Network errors, 429 and 5xx responses are retried
+// with exponential backoff and jitter, a Retry-After header takes precedence over the computed delay. A Retry-After
+// longer than MaxBackoff or the rest of MaxElapsed stops retrying, the last response is returned.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one, DefaultRetryMaxAttempts when zero.
+	MaxAttempts int `validate:"min=0"`
+	// MaxElapsed limits the time spent on all attempts and delays, zero means no limit.
+	MaxElapsed     time.Duration `validate:"min=0"`
+	InitialBackoff time.Duration `validate:"min=0"`
+	MaxBackoff     time.Duration `validate:"min=0"`
+}
+
+type retrySafeKey struct{}
+
+// WithRetrySafe marks requests sent with the context as safe to repeat, e.g. a POST that monobank
+// deduplicates by reference.
+func WithRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+func isRetrySafe(req *http.Request) bool {
+	if req.Method == http.MethodGet {
+		return true
+	}
+
+	safe, _ := req.Context().Value(retrySafeKey{}).(bool)
+
+	return safe
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return DefaultRetryMaxBackoff
+	}
+
+	return p.MaxBackoff
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultRetryInitialBackoff
+	}
+
+	limit := p.maxBackoff()
+	delay := initial
+
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, limit)
+
+	// equal jitter: keep half of the delay and randomize the other half
+	half := delay / 2
+
+	return half + rand.N(half+1) //nolint:gosec // jitter does not need a secure source
+}
+
+// delay returns the delay before the next attempt, it is false when the server asks to wait longer than MaxBackoff.
+func (p RetryPolicy) delay(attempt int, res *http.Response) (time.Duration, bool) {
+	if res != nil {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			return retryAfter, retryAfter <= p.maxBackoff()
+		}
+	}
+
+	return p.backoff(attempt), true
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			!errors.Is(err, ErrRateLimitDeadline)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	next.Body = body
+
+	return next, nil
+}
+
+// send returns the response of the last attempt and the number of attempts made.
+func (c *Client) send(req *http.Request) (*http.Response, int, error) {
+	policy := c.cnf.Retry
+
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	if policy == nil || !replayable || !isRetrySafe(req) {
+		res, err := c.do(req)
+
+		return res, 1, err
+	}
+
+	var (
+		ctx   = req.Context()
+		start = time.Now()
+	)
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.do(req)
+
+		if attempt >= policy.maxAttempts() || !isRetryable(res, err) {
+			return res, attempt, err
+		}
+
+		delay, ok := policy.delay(attempt, res)
+
+		if !ok || policy.MaxElapsed > 0 && time.Since(start)+delay > policy.MaxElapsed {
+			return res, attempt, err
+		}
+
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, attempt, errors.WithStack(ctx.Err())
+		case <-timer.C:
+		}
+
+		if req, err = rewindRequest(req); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
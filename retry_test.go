@@ -0,0 +1,291 @@
+package monoacquiring
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryTestClient(t *testing.T, srv *httptest.Server, policy *RetryPolicy) *Client {
+	t.Helper()
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL, Retry: policy}, srv.Client(), nil)
+
+	assert.NoError(t, err)
+
+	return client
+}
+
+func TestRetry_GET(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `{"errCode": "INTERNAL_ERROR","errText": ""}`)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"merchantId": "12o4Vv7EWy"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	res, err := client.GetMerchantDetails(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12o4Vv7EWy", res.MerchantID)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetry_Exhausted(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = fmt.Fprint(w, `{"errCode": "TOO_MANY_REQUESTS","errText": "too many requests"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Hour})
+
+	res, err := client.GetMerchantDetails(context.Background())
+
+	assert.ErrorIs(t, err, ErrTooManyRequestsHTTPStatus)
+	assert.Nil(t, res)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestRetry_POST(t *testing.T) {
+	tests := map[string]struct {
+		Ctx   context.Context
+		Calls int32
+	}{
+		"not marked": {
+			Ctx:   context.Background(),
+			Calls: 1,
+		},
+		"marked safe": {
+			Ctx:   WithRetrySafe(context.Background()),
+			Calls: 2,
+		},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				calls  atomic.Int32
+				bodies []string
+			)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/merchant/invoice/create", func(w http.ResponseWriter, req *http.Request) {
+				body, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(body))
+
+				if calls.Add(1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				_, _ = fmt.Fprint(w, `{"invoiceId": "p2_9ZgpZVsl3"}`)
+			})
+
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			client := newRetryTestClient(t, srv, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+			_, _ = client.CreateInvoice(val.Ctx, InvoiceCreateRequest{Amount: 100})
+
+			assert.Equal(t, val.Calls, calls.Load())
+
+			for _, body := range bodies {
+				assert.Equal(t, bodies[0], body, "request body must be replayed")
+			}
+		})
+	}
+}
+
+func TestRetry_Disabled(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, nil)
+
+	_, err := client.GetMerchantDetails(context.Background())
+
+	assert.ErrorIs(t, err, ErrInternalHTTPStatus)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetry_MaxElapsed(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{MaxAttempts: 5, MaxElapsed: time.Second, MaxBackoff: time.Hour})
+
+	_, err := client.GetMerchantDetails(context.Background())
+
+	assert.ErrorIs(t, err, ErrTooManyRequestsHTTPStatus)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetry_ZeroPolicy(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{})
+
+	_, err := client.GetMerchantDetails(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(DefaultRetryMaxAttempts), calls.Load())
+}
+
+func TestRetryPolicy_RetryAfterTooLong(t *testing.T) {
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+
+	delay, ok := RetryPolicy{}.delay(1, res)
+	assert.False(t, ok, "longer than DefaultRetryMaxBackoff")
+	assert.Equal(t, time.Hour, delay)
+
+	delay, ok = RetryPolicy{MaxBackoff: 2 * time.Hour}.delay(1, res)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, delay, "not shortened")
+}
+
+func TestRetry_RetryAfterTooLong(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{MaxAttempts: 5})
+
+	_, err := client.GetMerchantDetails(context.Background())
+
+	assert.ErrorIs(t, err, ErrTooManyRequestsHTTPStatus)
+	assert.Equal(t, int32(1), calls.Load(), "the last response is returned instead of retrying early")
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/details", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newRetryTestClient(t, srv, &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetMerchantDetails(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	}
+
+	for attempt, limit := range tests {
+		t.Run(fmt.Sprint(attempt), func(t *testing.T) {
+			delay := policy.backoff(attempt)
+
+			assert.GreaterOrEqual(t, delay, limit/2)
+			assert.LessOrEqual(t, delay, limit)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)
+
+	tests := map[string]struct {
+		Value string
+		Delay time.Duration
+		Ok    bool
+	}{
+		"empty":    {Value: "", Ok: false},
+		"seconds":  {Value: "3", Delay: 3 * time.Second, Ok: true},
+		"negative": {Value: "-3", Ok: false},
+		"date":     {Value: "Mon, 11 Aug 2025 06:09:02 GMT", Delay: 10 * time.Second, Ok: true},
+		"past":     {Value: "Mon, 11 Aug 2025 06:00:00 GMT", Delay: 0, Ok: true},
+		"invalid":  {Value: "soon", Ok: false},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(val.Value, now)
+
+			assert.Equal(t, val.Ok, ok)
+			assert.Equal(t, val.Delay, delay)
+		})
+	}
+}
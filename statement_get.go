@@ -27,6 +27,21 @@ type GetStatementResponse struct {
 }
 
 func (c *Client) GetStatement(ctx context.Context, payload GetStatementRequest) (*GetStatementResponse, error) {
+	req, err := c.newStatementRequest(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetStatementResponse
+
+	if err = c.doReq(req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) newStatementRequest(ctx context.Context, payload GetStatementRequest) (*http.Request, error) {
 	err := c.validator.StructCtx(ctx, payload)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -43,16 +58,5 @@ func (c *Client) GetStatement(ctx context.Context, payload GetStatementRequest)
 		query["to"] = strconv.FormatInt(util.PointerValue(payload.To).Unix(), 10)
 	}
 
-	req, err := c.newRequest(ctx, http.MethodGet, getStatementPath, query, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result GetStatementResponse
-
-	if err = c.doReq(req, &result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
+	return c.newRequest(ctx, http.MethodGet, getStatementPath, query, nil)
 }
@@ -80,6 +80,7 @@ func TestGetStatement(t *testing.T) {
 	assert.Equal(t, "444403******1902", res.List[0].MaskedPan)
 	assert.True(t, res.List[0].Status.IsSuccess())
 	assert.True(t, res.List[0].PaymentScheme.IsFull())
+	assert.False(t, res.List[0].Date.IsValid(), "null date")
 	assert.Len(t, res.List[0].CancelList, 1)
 }
 
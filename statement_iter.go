@@ -0,0 +1,153 @@
+package monoacquiring
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultStatementWindow is the longest period GetStatement accepts.
+const DefaultStatementWindow = 31 * 24 * time.Hour
+
+type StatementIterOptions struct {
+	Code *string
+	// Window is the period of one GetStatement request, DefaultStatementWindow when zero.
+	Window time.Duration
+	// Concurrency is the number of windows fetched at once, one when zero, so the windows are fetched sequentially.
+	Concurrency int
+}
+
+type statementWindow struct {
+	from time.Time
+	to   time.Time
+}
+
+type statementWindowResult struct {
+	err  error
+	list []Statement
+}
+
+func (o StatementIterOptions) windows(from, to time.Time) []statementWindow {
+	size := o.Window
+	if size <= 0 {
+		size = DefaultStatementWindow
+	}
+
+	var windows []statementWindow
+
+	for start := from; start.Before(to); start = start.Add(size) {
+		windows = append(windows, statementWindow{from: start, to: minTime(start.Add(size), to)})
+	}
+
+	return windows
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+// StatementIter returns the statements of the period from-to, split into windows of StatementIterOptions.Window.
+//
+// Statements are yielded window by window in chronological order of the windows, a statement found in two
+// windows is yielded once. The first error is yielded with an empty Statement and ends the iteration.
+func (c *Client) StatementIter(
+	ctx context.Context,
+	from, to time.Time,
+	opts StatementIterOptions,
+) iter.Seq2[Statement, error] {
+	return func(yield func(Statement, error) bool) {
+		if !from.Before(to) {
+			yield(Statement{}, errors.Errorf("statement period %s - %s is empty", from, to))
+
+			return
+		}
+
+		windows := opts.windows(from, to)
+		results := make([]chan statementWindowResult, len(windows))
+
+		for i := range results {
+			results[i] = make(chan statementWindowResult, 1)
+		}
+
+		var wg sync.WaitGroup
+
+		defer wg.Wait()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// a slot is taken when a window is fetched and released when it is consumed,
+		// so at most Concurrency windows are held in memory
+		slots := make(chan struct{}, max(opts.Concurrency, 1))
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i, window := range windows {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					res, err := c.GetStatement(ctx, GetStatementRequest{From: window.from, To: &window.to, Code: opts.Code})
+					if err != nil {
+						results[i] <- statementWindowResult{err: err}
+
+						return
+					}
+
+					results[i] <- statementWindowResult{list: res.List}
+				}()
+			}
+		}()
+
+		seen := make(map[string]struct{})
+
+		for _, ch := range results {
+			var result statementWindowResult
+
+			select {
+			case result = <-ch:
+			case <-ctx.Done():
+				yield(Statement{}, errors.WithStack(ctx.Err()))
+
+				return
+			}
+
+			<-slots
+
+			if result.err != nil {
+				yield(Statement{}, result.err)
+
+				return
+			}
+
+			for _, statement := range result.list {
+				if _, ok := seen[statement.InvoiceID]; ok {
+					continue
+				}
+
+				seen[statement.InvoiceID] = struct{}{}
+
+				if !yield(statement, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package monoacquiring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatementIterTestClient serves one statement per hour, the one of the window end hour is in two windows.
+func newStatementIterTestClient(t *testing.T, failFrom int64) (*Client, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+
+	var calls, inFlight, maxInFlight atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/statement", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			prev := maxInFlight.Load()
+			if current <= prev || maxInFlight.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+
+		if failFrom != 0 && from >= failFrom {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"errCode": "BAD_REQUEST","errText": "invalid period"}`)
+
+			return
+		}
+
+		res := GetStatementResponse{List: []Statement{}}
+
+		for ts := from; ts <= to; ts += 3600 {
+			res.List = append(res.List, Statement{
+				InvoiceID: strconv.FormatInt(ts, 10),
+				Date:      NewTimestamp(time.Unix(ts, 0).UTC()),
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(res)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	return client, &calls, &maxInFlight
+}
+
+func collectStatements(t *testing.T, client *Client, from, to time.Time, opts StatementIterOptions) ([]string, error) {
+	t.Helper()
+
+	var ids []string
+
+	for statement, err := range client.StatementIter(context.Background(), from, to, opts) {
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, statement.InvoiceID)
+	}
+
+	return ids, nil
+}
+
+func TestStatementIter(t *testing.T) {
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+
+	expected := make([]string, 0, 11)
+	for ts := from; !ts.After(to); ts = ts.Add(time.Hour) {
+		expected = append(expected, strconv.FormatInt(ts.Unix(), 10))
+	}
+
+	for name, val := range map[string]struct {
+		Opts        StatementIterOptions
+		Calls       int32
+		MaxInFlight int32
+	}{
+		"one window": {Opts: StatementIterOptions{}, Calls: 1, MaxInFlight: 1},
+		"sequential": {Opts: StatementIterOptions{Window: 3 * time.Hour}, Calls: 4, MaxInFlight: 1},
+		"concurrent": {Opts: StatementIterOptions{Window: time.Hour, Concurrency: 3}, Calls: 10, MaxInFlight: 3},
+		"unbalanced": {Opts: StatementIterOptions{Window: 4 * time.Hour, Concurrency: 8}, Calls: 3, MaxInFlight: 3},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, calls, maxInFlight := newStatementIterTestClient(t, 0)
+
+			ids, err := collectStatements(t, client, from, to, val.Opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, expected, ids, "ordered and without duplicates")
+			assert.Equal(t, val.Calls, calls.Load())
+			assert.LessOrEqual(t, maxInFlight.Load(), val.MaxInFlight)
+		})
+	}
+}
+
+func TestStatementIter_Break(t *testing.T) {
+	client, calls, _ := newStatementIterTestClient(t, 0)
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	var count int
+
+	for _, err := range client.StatementIter(context.Background(), from, from.Add(24*time.Hour), StatementIterOptions{
+		Window:      time.Hour,
+		Concurrency: 2,
+	}) {
+		require.NoError(t, err)
+
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	assert.LessOrEqual(t, calls.Load(), int32(4), "no windows are fetched after the break")
+}
+
+func TestStatementIter_Error(t *testing.T) {
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	client, _, _ := newStatementIterTestClient(t, from.Add(2*time.Hour).Unix())
+
+	ids, err := collectStatements(t, client, from, from.Add(5*time.Hour), StatementIterOptions{
+		Window:      time.Hour,
+		Concurrency: 2,
+	})
+
+	assert.ErrorIs(t, err, ErrBadRequestHTTPStatus)
+	assert.Len(t, ids, 3, "statements before the failed window are yielded")
+
+	_, err = collectStatements(t, client, from, from, StatementIterOptions{})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, err = range client.StatementIter(ctx, from, from.Add(time.Hour), StatementIterOptions{}) {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
@@ -0,0 +1,113 @@
+package monoacquiring
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// StatementFunc receives the statements of StreamStatement one by one, a returned error stops the decoding.
+type StatementFunc func(statement Statement) error
+
+// StreamStatement requests the statement like GetStatement, but decodes the list element by element and passes
+// every statement to fn instead of keeping the whole list in memory.
+//
+// Statements passed to fn before an error are not revoked, the error of fn is returned unchanged.
+func (c *Client) StreamStatement(ctx context.Context, payload GetStatementRequest, fn StatementFunc) error {
+	req, err := c.newStatementRequest(context.WithValue(ctx, streamingKey{}, true), payload)
+	if err != nil {
+		return err
+	}
+
+	return c.doReq(req, &statementStream{fn: fn})
+}
+
+type streamingKey struct{}
+
+// isStreaming reports whether the response of the request is decoded while it is read, so a middleware must not
+// read the successful response body ahead of the client.
+func isStreaming(req *http.Request) bool {
+	streaming, _ := req.Context().Value(streamingKey{}).(bool)
+
+	return streaming
+}
+
+type statementStream struct {
+	fn StatementFunc
+}
+
+func (s *statementStream) decodeBody(body io.Reader) error {
+	dec := json.NewDecoder(body)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if key, _ := token.(string); key != "list" {
+			var skip json.RawMessage
+
+			if err = dec.Decode(&skip); err != nil {
+				return errors.WithStack(err)
+			}
+
+			continue
+		}
+
+		if err = s.decodeList(dec); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func (s *statementStream) decodeList(dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.Errorf("statement list is %v, not an array", token)
+	}
+
+	for dec.More() {
+		var statement Statement
+
+		if err = dec.Decode(&statement); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err = s.fn(statement); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, expected json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return errors.Errorf("unexpected %v in statement response, %v expected", token, expected)
+	}
+
+	return nil
+}
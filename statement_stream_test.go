@@ -0,0 +1,195 @@
+package monoacquiring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamStatement(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/statement", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{
+  "total": {"count": 3},
+  "list": [
+    {"invoiceId": "1", "status": "success", "amount": 100, "ccy": 980, "date": "2025-08-11T06:08:52Z"},
+    {"invoiceId": "2", "status": "success", "amount": 200, "ccy": 980, "date": null},
+    {"invoiceId": "3", "status": "hold", "amount": 300, "ccy": 980}
+  ],
+  "next": null
+}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	var statements []Statement
+
+	err = client.StreamStatement(context.Background(), GetStatementRequest{From: time.Unix(1755692087, 0)},
+		func(statement Statement) error {
+			statements = append(statements, statement)
+
+			return nil
+		})
+
+	require.NoError(t, err)
+	require.Len(t, statements, 3)
+	assert.Equal(t, "1", statements[0].InvoiceID)
+	assert.Equal(t, "3.00 UAH", statements[2].Money().String())
+	assert.True(t, statements[2].Status.IsHold())
+
+	stop := errors.New("stop")
+
+	var count int
+
+	err = client.StreamStatement(context.Background(), GetStatementRequest{From: time.Unix(1755692087, 0)},
+		func(Statement) error {
+			count++
+
+			return stop
+		})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, count)
+}
+
+func TestStatementStream_DecodeBody(t *testing.T) {
+	for name, val := range map[string]struct {
+		Body  string
+		Count int
+		Err   bool
+	}{
+		"empty list":  {Body: `{"list": []}`},
+		"null list":   {Body: `{"list": null}`},
+		"no list":     {Body: `{}`},
+		"list first":  {Body: `{"list": [{"invoiceId": "1"}], "extra": {"list": []}}`, Count: 1},
+		"not object":  {Body: `[]`, Err: true},
+		"not array":   {Body: `{"list": {}}`, Err: true},
+		"bad element": {Body: `{"list": [{"invoiceId": 1}]}`, Err: true},
+		"truncated":   {Body: `{"list": [{"invoiceId": "1"}`, Count: 1, Err: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var count int
+
+			stream := &statementStream{fn: func(Statement) error {
+				count++
+
+				return nil
+			}}
+
+			err := stream.decodeBody(strings.NewReader(val.Body))
+
+			assert.Equal(t, val.Err, err != nil, err)
+			assert.Equal(t, val.Count, count)
+		})
+	}
+}
+
+func statementBody(b *testing.B, size int) []byte {
+	b.Helper()
+
+	res := GetStatementResponse{List: make([]Statement, size)}
+
+	for i := range res.List {
+		res.List[i] = Statement{
+			InvoiceID:     fmt.Sprintf("2205175v4MfatvmUL%06d", i),
+			MaskedPan:     "444403******1902",
+			Date:          NewTimestamp(time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)),
+			Status:        StatementStatus("success"),
+			PaymentScheme: StatementPaymentScheme("full"),
+			Amount:        4200,
+			Currency:      CurrencyUAH,
+		}
+	}
+
+	body, err := json.Marshal(res)
+	require.NoError(b, err)
+
+	return body
+}
+
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapInuse
+}
+
+func heapGrowth(base uint64) uint64 {
+	return max(heapInUse(), base) - base
+}
+
+// BenchmarkStatementDecode compares the peak heap growth of decoding the whole response and of streaming it,
+// reported as peak-heap-B: it grows with the list for "full" and stays flat for "stream".
+func BenchmarkStatementDecode(b *testing.B) {
+	for _, size := range []int{1_000, 10_000, 100_000} {
+		body := statementBody(b, size)
+
+		b.Run(fmt.Sprintf("full/%d", size), func(b *testing.B) {
+			var peak uint64
+
+			b.ReportAllocs()
+
+			for range b.N {
+				runtime.GC()
+				base := heapInUse()
+
+				var res GetStatementResponse
+
+				if err := json.NewDecoder(bytes.NewReader(body)).Decode(&res); err != nil {
+					b.Fatal(err)
+				}
+
+				peak = max(peak, heapGrowth(base))
+
+				runtime.KeepAlive(res)
+			}
+
+			b.ReportMetric(float64(peak), "peak-heap-B")
+		})
+
+		b.Run(fmt.Sprintf("stream/%d", size), func(b *testing.B) {
+			var peak uint64
+
+			b.ReportAllocs()
+
+			for range b.N {
+				runtime.GC()
+				base := heapInUse()
+
+				var count int
+
+				stream := &statementStream{fn: func(Statement) error {
+					if count++; count%1_000 == 0 {
+						runtime.GC()
+						peak = max(peak, heapGrowth(base))
+					}
+
+					return nil
+				}}
+
+				if err := stream.decodeBody(bytes.NewReader(body)); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(peak), "peak-heap-B")
+		})
+	}
+}
@@ -18,26 +18,26 @@ type SyncPaymentRequest struct {
 	GooglePay           *GooglePay           `json:"googlePay,omitempty" validate:"omitempty"`
 	ApplePay            *ApplePay            `json:"applePay,omitempty" validate:"omitempty"`
 	SyncPaymentCard     *SyncPaymentCard     `json:"cardData,omitempty" validate:"omitempty"`
-	Currency            int                  `json:"ccy" validate:"required,iso4217_numeric"`
+	Currency            Currency             `json:"ccy" validate:"required,iso4217_numeric"`
 	Amount              int64                `json:"amount" validate:"required"`
 }
 
 type SyncPaymentResponse struct {
-	FinalAmount   *int64            `json:"finalAmount,omitempty"`
-	CreatedDate   *string           `json:"createdDate,omitempty"`
-	FailureReason *string           `json:"failureReason,omitempty"`
-	ErrCode       *string           `json:"errCode,omitempty"`
-	TipsInfo      *TipsInfo         `json:"tipsInfo,omitempty"`
-	WalletData    *WalletData       `json:"walletData,omitempty"`
-	PaymentInfo   *PaymentInfo      `json:"paymentInfo,omitempty"`
-	Destination   *string           `json:"destination,omitempty"`
-	ModifiedDate  *string           `json:"modifiedDate,omitempty"`
-	Reference     *string           `json:"reference,omitempty"`
-	InvoiceID     string            `json:"invoiceId"`
-	Status        SyncPaymentStatus `json:"status"`
-	CancelList    []CancelListItem  `json:"cancelList"`
-	Currency      int               `json:"ccy"`
-	Amount        int64             `json:"amount"`
+	FinalAmount   *int64           `json:"finalAmount,omitempty"`
+	CreatedDate   Timestamp        `json:"createdDate,omitempty"`
+	FailureReason *string          `json:"failureReason,omitempty"`
+	ErrCode       *string          `json:"errCode,omitempty"`
+	TipsInfo      *TipsInfo        `json:"tipsInfo,omitempty"`
+	WalletData    *WalletData      `json:"walletData,omitempty"`
+	PaymentInfo   *PaymentInfo     `json:"paymentInfo,omitempty"`
+	Destination   *string          `json:"destination,omitempty"`
+	ModifiedDate  Timestamp        `json:"modifiedDate,omitempty"`
+	Reference     *string          `json:"reference,omitempty"`
+	InvoiceID     string           `json:"invoiceId"`
+	Status        InvoiceStatus    `json:"status"`
+	CancelList    []CancelListItem `json:"cancelList"`
+	Currency      Currency         `json:"ccy"`
+	Amount        int64            `json:"amount"`
 }
 
 func (c *Client) SyncPayment(ctx context.Context, payload SyncPaymentRequest) (*SyncPaymentResponse, error) {
@@ -46,6 +46,11 @@ func (c *Client) SyncPayment(ctx context.Context, payload SyncPaymentRequest) (*
 		return nil, errors.WithStack(err)
 	}
 
+	key, err := c.beginAttempt(ctx, OperationSyncPayment, payload.MerchantPaymentInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := new(bytes.Buffer)
 	if err = json.NewEncoder(buf).Encode(payload); err != nil {
 		return nil, errors.Wrap(err, "failed to marshal sync payment request")
@@ -58,7 +63,14 @@ func (c *Client) SyncPayment(ctx context.Context, payload SyncPaymentRequest) (*
 
 	var result SyncPaymentResponse
 
-	if err = c.doReq(req, &result); err != nil {
+	err = c.doReq(req, &result)
+	err = c.endAttempt(ctx, key, result.InvoiceID, err)
+
+	if errors.Is(err, ErrPaymentNotRecorded) {
+		return &result, err
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
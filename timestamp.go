@@ -0,0 +1,94 @@
+package monoacquiring
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// timestampLayouts are tried in order, layouts without a zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Timestamp is a date of the API decoded into time.Time, keeping the value as it was received in Raw.
+//
+// Decoding is tolerant: null gives the zero Timestamp, and a value in an unknown format keeps only Raw,
+// leaving Time zero, instead of failing the whole response.
+type Timestamp struct {
+	time.Time
+	Raw string
+}
+
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t, Raw: t.Format(time.RFC3339)}
+}
+
+// ParseTimestamp parses the value with the layouts used by the API.
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{Time: t, Raw: value}, nil
+		}
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return Timestamp{Time: time.Unix(seconds, 0).UTC(), Raw: value}, nil
+	}
+
+	return Timestamp{Raw: value}, errors.Errorf("unknown timestamp format %q", value)
+}
+
+// IsValid reports whether the timestamp was received and parsed.
+func (t Timestamp) IsValid() bool {
+	return !t.Time.IsZero()
+}
+
+func (t Timestamp) String() string {
+	if t.Raw != "" {
+		return t.Raw
+	}
+
+	if t.Time.IsZero() {
+		return ""
+	}
+
+	return t.Time.Format(time.RFC3339)
+}
+
+// MarshalJSON writes Raw when it is set, so a decoded value is encoded unchanged.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	value := t.String()
+	if value == "" {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(value)
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+
+		return nil
+	}
+
+	var value string
+
+	if len(data) > 0 && data[0] != '"' {
+		value = string(data)
+	} else if err := json.Unmarshal(data, &value); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*t, _ = ParseTimestamp(value)
+
+	return nil
+}
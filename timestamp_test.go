@@ -0,0 +1,72 @@
+package monoacquiring
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	for name, val := range map[string]struct {
+		Expected time.Time
+		JSON     string
+		Raw      string
+	}{
+		"rfc3339":         {JSON: `"2025-07-17T12:00:00+03:00"`, Raw: "2025-07-17T12:00:00+03:00", Expected: time.Date(2025, 7, 17, 9, 0, 0, 0, time.UTC)},
+		"utc":             {JSON: `"2025-08-11T06:08:52Z"`, Raw: "2025-08-11T06:08:52Z", Expected: time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)},
+		"fraction":        {JSON: `"2025-08-11T06:08:52.123Z"`, Raw: "2025-08-11T06:08:52.123Z", Expected: time.Date(2025, 8, 11, 6, 8, 52, 123000000, time.UTC)},
+		"offset no colon": {JSON: `"2025-07-17T12:00:00+0300"`, Raw: "2025-07-17T12:00:00+0300", Expected: time.Date(2025, 7, 17, 9, 0, 0, 0, time.UTC)},
+		"no zone":         {JSON: `"2025-08-11T06:08:52"`, Raw: "2025-08-11T06:08:52", Expected: time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)},
+		"space":           {JSON: `"2025-08-11 06:08:52"`, Raw: "2025-08-11 06:08:52", Expected: time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)},
+		"unix":            {JSON: `1754892532`, Raw: "1754892532", Expected: time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)},
+		"null":            {JSON: `null`},
+		"empty":           {JSON: `""`},
+		"unknown":         {JSON: `"11.08.2025"`, Raw: "11.08.2025"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var ts Timestamp
+
+			require.NoError(t, json.Unmarshal([]byte(val.JSON), &ts))
+			assert.Equal(t, val.Raw, ts.Raw)
+			assert.True(t, val.Expected.Equal(ts.Time), "got %s", ts.Time)
+			assert.Equal(t, !val.Expected.IsZero(), ts.IsValid())
+		})
+	}
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	var value struct {
+		Created  Timestamp `json:"created"`
+		Modified Timestamp `json:"modified"`
+		Empty    Timestamp `json:"empty"`
+	}
+
+	body := `{"created":"2025-07-17T12:00:00+03:00","modified":"11.08.2025","empty":null}`
+
+	require.NoError(t, json.Unmarshal([]byte(body), &value))
+
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	assert.JSONEq(t, body, string(encoded), "raw values are kept")
+
+	encoded, err = json.Marshal(NewTimestamp(time.Date(2025, 8, 11, 6, 8, 52, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, `"2025-08-11T06:08:52Z"`, string(encoded))
+}
+
+func TestTimestamp_Sort(t *testing.T) {
+	var list []Statement
+
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"invoiceId": "b", "date": "2025-08-11T09:00:00+03:00"},
+		{"invoiceId": "a", "date": "2025-08-11T05:00:00Z"}
+	]`), &list))
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date.Time) })
+
+	assert.Equal(t, "a", list[0].InvoiceID)
+}
@@ -20,19 +20,19 @@ type TokenPaymentRequest struct {
 	CardToken           string               `json:"cardToken" validate:"required"`
 	InitiationKind      string               `json:"initiationKind" validate:"required,oneof=merchant client"`
 	PaymentType         string               `json:"paymentType" validate:"required,oneof=debit hold"`
-	Currency            int                  `json:"ccy" validate:"required,iso4217_numeric"`
+	Currency            Currency             `json:"ccy" validate:"required,iso4217_numeric"`
 	Amount              int64                `json:"amount" validate:"required"`
 }
 
 type TokenPaymentResponse struct {
-	InvoiceID     string             `json:"invoiceId"`
-	TdsURL        string             `json:"tdsUrl"`
-	Status        TokenPaymentStatus `json:"status"`
-	FailureReason *string            `json:"failureReason,omitempty"`
-	CreatedDate   string             `json:"createdDate"`
-	ModifiedDate  string             `json:"modifiedDate"`
-	Amount        int64              `json:"amount"`
-	Currency      int                `json:"ccy"`
+	InvoiceID     string        `json:"invoiceId"`
+	TdsURL        string        `json:"tdsUrl"`
+	Status        InvoiceStatus `json:"status"`
+	FailureReason *string       `json:"failureReason,omitempty"`
+	CreatedDate   Timestamp     `json:"createdDate"`
+	ModifiedDate  Timestamp     `json:"modifiedDate"`
+	Amount        int64         `json:"amount"`
+	Currency      Currency      `json:"ccy"`
 }
 
 func (c *Client) TokenPayment(ctx context.Context, payload TokenPaymentRequest) (*TokenPaymentResponse, error) {
@@ -45,6 +45,11 @@ func (c *Client) TokenPayment(ctx context.Context, payload TokenPaymentRequest)
 		return nil, errors.WithStack(err)
 	}
 
+	key, err := c.beginAttempt(ctx, OperationTokenPayment, payload.MerchantPaymentInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := new(bytes.Buffer)
 	if err = json.NewEncoder(buf).Encode(payload); err != nil {
 		return nil, errors.Wrap(err, "failed to marshal token payment request")
@@ -57,7 +62,14 @@ func (c *Client) TokenPayment(ctx context.Context, payload TokenPaymentRequest)
 
 	var result TokenPaymentResponse
 
-	if err = c.doReq(req, &result); err != nil {
+	err = c.doReq(req, &result)
+	err = c.endAttempt(ctx, key, result.InvoiceID, err)
+
+	if errors.Is(err, ErrPaymentNotRecorded) {
+		return &result, err
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,153 @@
+package monoacquiring
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultWaitInitialInterval = time.Second
+	DefaultWaitMaxInterval     = 15 * time.Second
+)
+
+// InvoiceNotifier lets WaitForInvoice learn about a status change, e.g. from a webhook, before the next poll.
+type InvoiceNotifier interface {
+	// Subscribe returns the channel receiving the statuses published for the invoice and a function releasing it.
+	Subscribe(invoiceID string) (<-chan InvoiceStatus, func())
+	Publish(invoiceID string, status InvoiceStatus)
+}
+
+type WaitOptions struct {
+	// Notifier short-circuits the polling interval when a status of the invoice is published.
+	Notifier InvoiceNotifier
+	// Until reports whether the status is the awaited one, by default any status after created and processing.
+	Until func(status InvoiceStatus) bool
+	// InitialInterval is the delay before the second poll, it doubles up to MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+func (o WaitOptions) until(status InvoiceStatus) bool {
+	if o.Until != nil {
+		return o.Until(status)
+	}
+
+	return status.IsValid() && !status.IsCreated() && !status.IsProcessing()
+}
+
+func (o WaitOptions) backoff(attempt int) time.Duration {
+	policy := RetryPolicy{InitialBackoff: o.InitialInterval, MaxBackoff: o.MaxInterval}
+
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultWaitInitialInterval
+	}
+
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultWaitMaxInterval
+	}
+
+	return policy.backoff(attempt)
+}
+
+// WaitForInvoice polls GetInvoiceStatus until the invoice reaches the awaited status, see WaitOptions.Until.
+//
+// Retryable errors are polled over, any other error or the end of the context stops waiting and is returned
+// together with the last received status, which is nil when no poll succeeded.
+func (c *Client) WaitForInvoice(
+	ctx context.Context,
+	invoiceID string,
+	opts WaitOptions,
+) (*GetInvoiceStatusResponse, error) {
+	var notifications <-chan InvoiceStatus
+
+	if opts.Notifier != nil {
+		ch, unsubscribe := opts.Notifier.Subscribe(invoiceID)
+		defer unsubscribe()
+
+		notifications = ch
+	}
+
+	var last *GetInvoiceStatusResponse
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.GetInvoiceStatus(ctx, GetInvoiceStatusRequest{InvoiceID: invoiceID})
+
+		switch {
+		case err == nil:
+			last = res
+
+			if opts.until(res.Status) {
+				return res, nil
+			}
+		case !IsRetryable(err):
+			return last, err
+		}
+
+		timer := time.NewTimer(opts.backoff(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return last, errors.WithStack(ctx.Err())
+		case _, ok := <-notifications:
+			timer.Stop()
+
+			// a closed channel is always ready, waiting falls back to the polling interval
+			if !ok {
+				notifications = nil
+			}
+		case <-timer.C:
+		}
+	}
+}
+
+// MemoryNotifier is an in-process InvoiceNotifier, publish to it from the webhook handler.
+type MemoryNotifier struct {
+	subscribers map[string]map[chan InvoiceStatus]struct{}
+	mu          sync.Mutex
+}
+
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{subscribers: make(map[string]map[chan InvoiceStatus]struct{})}
+}
+
+func (n *MemoryNotifier) Subscribe(invoiceID string) (<-chan InvoiceStatus, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ch := make(chan InvoiceStatus, 1)
+
+	if n.subscribers[invoiceID] == nil {
+		n.subscribers[invoiceID] = make(map[chan InvoiceStatus]struct{})
+	}
+
+	n.subscribers[invoiceID][ch] = struct{}{}
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		delete(n.subscribers[invoiceID], ch)
+
+		if len(n.subscribers[invoiceID]) == 0 {
+			delete(n.subscribers, invoiceID)
+		}
+	}
+}
+
+// Publish never blocks, a subscriber that has not received the previous status yet misses this one.
+func (n *MemoryNotifier) Publish(invoiceID string, status InvoiceStatus) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers[invoiceID] {
+		select {
+		case ch <- status:
+		default:
+		}
+	}
+}
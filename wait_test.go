@@ -0,0 +1,154 @@
+package monoacquiring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWaitTestServer(t *testing.T, statuses ...string) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/invoice/status", func(w http.ResponseWriter, _ *http.Request) {
+		status := statuses[min(int(calls.Add(1)), len(statuses))-1]
+
+		switch status {
+		case "500":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `{"errCode": "INTERNAL_ERROR","errText": ""}`)
+		case "404":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"errCode": "NOT_FOUND","errText": "invoice not found"}`)
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintf(w, `{"invoiceId": "p2_9ZgpZVsl3","status": %q,"amount": 4200,"ccy": 980}`, status)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(Config{APIKey: "test", BaseURL: srv.URL}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	return client, &calls
+}
+
+func TestWaitForInvoice(t *testing.T) {
+	client, calls := newWaitTestServer(t, "created", "processing", "500", "success")
+
+	res, err := client.WaitForInvoice(context.Background(), "p2_9ZgpZVsl3", WaitOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, res.Status.IsSuccess())
+	assert.Equal(t, int32(4), calls.Load(), "the internal error is polled over")
+}
+
+func TestWaitForInvoice_Until(t *testing.T) {
+	client, calls := newWaitTestServer(t, "created", "hold", "success")
+
+	res, err := client.WaitForInvoice(context.Background(), "p2_9ZgpZVsl3", WaitOptions{
+		Until:           InvoiceStatus.IsSuccess,
+		InitialInterval: time.Millisecond,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, res.Status.IsSuccess())
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestWaitForInvoice_Notifier(t *testing.T) {
+	client, calls := newWaitTestServer(t, "created", "success")
+	notifier := NewMemoryNotifier()
+
+	go func() {
+		for calls.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// the subscription is made before the first poll
+		notifier.Publish("other", InvoiceStatusSuccess)
+		notifier.Publish("p2_9ZgpZVsl3", InvoiceStatusSuccess)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := client.WaitForInvoice(ctx, "p2_9ZgpZVsl3", WaitOptions{
+		Notifier:        notifier,
+		InitialInterval: time.Hour,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, res.Status.IsSuccess())
+	assert.Equal(t, int32(2), calls.Load())
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+
+	assert.Empty(t, notifier.subscribers, "unsubscribed")
+}
+
+type closedNotifier struct{}
+
+func (closedNotifier) Subscribe(string) (<-chan InvoiceStatus, func()) {
+	ch := make(chan InvoiceStatus)
+	close(ch)
+
+	return ch, func() {}
+}
+
+func (closedNotifier) Publish(string, InvoiceStatus) {}
+
+func TestWaitForInvoice_ClosedNotifier(t *testing.T) {
+	client, calls := newWaitTestServer(t, "created")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForInvoice(ctx, "p2_9ZgpZVsl3", WaitOptions{
+		Notifier:        closedNotifier{},
+		InitialInterval: time.Hour,
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(2), calls.Load(), "a closed channel falls back to the polling interval")
+}
+
+func TestWaitForInvoice_Error(t *testing.T) {
+	t.Run("context", func(t *testing.T) {
+		client, _ := newWaitTestServer(t, "created")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		res, err := client.WaitForInvoice(ctx, "p2_9ZgpZVsl3", WaitOptions{InitialInterval: time.Millisecond})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotNil(t, res, "the last status is returned")
+		assert.True(t, res.Status.IsCreated())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client, calls := newWaitTestServer(t, "404")
+
+		res, err := client.WaitForInvoice(context.Background(), "p2_9ZgpZVsl3", WaitOptions{})
+
+		assert.True(t, IsInvoiceNotFound(err))
+		assert.Nil(t, res)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+)
+
+const DefaultEventStoreSize = 10_000
+
+// EventMiddleware wraps the event callback. Middlewares passed in HandlerConfig are applied in order,
+// the first one is the outermost.
+type EventMiddleware func(next InvoiceEventFunc) InvoiceEventFunc
+
+func chainMiddlewares(onEvent InvoiceEventFunc, middlewares []EventMiddleware) InvoiceEventFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		onEvent = middlewares[i](onEvent)
+	}
+
+	return onEvent
+}
+
+// EventKey identifies a delivery of the invoice state, redeliveries of the same state have the same key.
+type EventKey struct {
+	InvoiceID string
+	Status    string
+	// ModifiedDate is the raw modifiedDate of the event.
+	ModifiedDate string
+}
+
+func NewEventKey(event InvoiceEvent) EventKey {
+	return EventKey{InvoiceID: event.InvoiceID, Status: event.Status.String(), ModifiedDate: event.ModifiedDate.Raw}
+}
+
+// EventStore remembers the processed events.
+type EventStore interface {
+	// Seen reports whether the event with the key was processed.
+	Seen(ctx context.Context, key EventKey) (bool, error)
+	// LastEvent returns the modifiedDate and the status of the last processed event of the invoice, zero values
+	// when there is none.
+	LastEvent(ctx context.Context, invoiceID string) (time.Time, monoacquiring.InvoiceStatus, error)
+	// Save records the processed event, an event as recent as the last one replaces its status.
+	Save(ctx context.Context, key EventKey, modified time.Time) error
+}
+
+// DedupMiddleware drops the events already processed and the events older than the last processed event of
+// the invoice, e.g. processing delivered after success. The modifiedDate has seconds, an event of the same second
+// is dropped when monoacquiring.CanTransition does not allow its status after the last one. A dropped event is
+// acknowledged, so it is not delivered again. An event is recorded only once the callback succeeds, so a failed
+// one is processed on redelivery; concurrent deliveries of the same event may both pass.
+func DedupMiddleware(store EventStore) EventMiddleware {
+	return func(next InvoiceEventFunc) InvoiceEventFunc {
+		return func(ctx context.Context, event InvoiceEvent) error {
+			key := NewEventKey(event)
+
+			seen, err := store.Seen(ctx, key)
+			if err != nil || seen {
+				return err
+			}
+
+			modified := event.ModifiedDate.Time
+
+			if event.ModifiedDate.IsValid() {
+				last, status, err := store.LastEvent(ctx, event.InvoiceID)
+				if err != nil {
+					return err
+				}
+
+				if modified.Before(last) {
+					return nil
+				}
+
+				if modified.Equal(last) && status != "" && !monoacquiring.CanTransition(status, event.Status) {
+					return nil
+				}
+			}
+
+			if err = next(ctx, event); err != nil {
+				return err
+			}
+
+			return store.Save(ctx, key, modified)
+		}
+	}
+}
+
+type invoiceEvents struct {
+	modified  time.Time
+	keys      map[EventKey]struct{}
+	invoiceID string
+	status    monoacquiring.InvoiceStatus
+}
+
+// MemoryEventStore is an in-process EventStore remembering the events of the most recently updated invoices.
+// The keys of an invoice older than its last modifiedDate are forgotten, such events are dropped as out of order.
+type MemoryEventStore struct {
+	invoices map[string]*list.Element
+	order    *list.List
+	size     int
+	mu       sync.Mutex
+}
+
+// NewMemoryEventStore returns a store of the given number of invoices, DefaultEventStoreSize when not positive.
+func NewMemoryEventStore(size int) *MemoryEventStore {
+	if size <= 0 {
+		size = DefaultEventStoreSize
+	}
+
+	return &MemoryEventStore{invoices: make(map[string]*list.Element), order: list.New(), size: size}
+}
+
+func (s *MemoryEventStore) Seen(_ context.Context, key EventKey) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.get(key.InvoiceID)
+	if !ok {
+		return false, nil
+	}
+
+	_, seen := events.keys[key]
+
+	return seen, nil
+}
+
+func (s *MemoryEventStore) LastEvent(
+	_ context.Context,
+	invoiceID string,
+) (time.Time, monoacquiring.InvoiceStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.get(invoiceID)
+	if !ok {
+		return time.Time{}, "", nil
+	}
+
+	return events.modified, events.status, nil
+}
+
+func (s *MemoryEventStore) Save(_ context.Context, key EventKey, modified time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.get(key.InvoiceID)
+	if !ok {
+		events = &invoiceEvents{invoiceID: key.InvoiceID, keys: make(map[EventKey]struct{})}
+		s.invoices[key.InvoiceID] = s.order.PushFront(events)
+
+		if s.order.Len() > s.size {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.invoices, oldest.Value.(*invoiceEvents).invoiceID)
+		}
+	}
+
+	if modified.After(events.modified) {
+		events.modified = modified
+		clear(events.keys)
+	}
+
+	if modified.Equal(events.modified) {
+		events.status = monoacquiring.InvoiceStatus(key.Status)
+	}
+
+	events.keys[key] = struct{}{}
+
+	return nil
+}
+
+// get returns the events of the invoice and marks it as recently used.
+func (s *MemoryEventStore) get(invoiceID string) (*invoiceEvents, bool) {
+	element, ok := s.invoices[invoiceID]
+	if !ok {
+		return nil, false
+	}
+
+	s.order.MoveToFront(element)
+
+	return element.Value.(*invoiceEvents), true
+}
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(t *testing.T, invoiceID string, status monoacquiring.InvoiceStatus, modified string) InvoiceEvent {
+	t.Helper()
+
+	event := InvoiceEvent{InvoiceID: invoiceID, Status: status}
+	if modified == "" {
+		return event
+	}
+
+	date, err := monoacquiring.ParseTimestamp(modified)
+	require.NoError(t, err)
+
+	event.ModifiedDate = date
+
+	return event
+}
+
+func TestDedupMiddleware(t *testing.T) {
+	var processed []string
+
+	failure := errors.New("storage is down")
+	fail := false
+
+	onEvent := DedupMiddleware(NewMemoryEventStore(0))(func(_ context.Context, event InvoiceEvent) error {
+		if fail {
+			return failure
+		}
+
+		processed = append(processed, event.InvoiceID+":"+event.Status.String())
+
+		return nil
+	})
+
+	ctx := context.Background()
+
+	for _, event := range []InvoiceEvent{
+		testEvent(t, "1", monoacquiring.InvoiceStatusProcessing, "2025-08-11T06:08:50Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusSuccess, "2025-08-11T06:08:52Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusSuccess, "2025-08-11T06:08:52Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusProcessing, "2025-08-11T06:08:50Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusProcessing, "2025-08-11T06:08:51Z"),
+		testEvent(t, "2", monoacquiring.InvoiceStatusProcessing, "2025-08-11T06:08:50Z"),
+		testEvent(t, "2", monoacquiring.InvoiceStatusProcessing, ""),
+		testEvent(t, "2", monoacquiring.InvoiceStatusProcessing, ""),
+		testEvent(t, "1", monoacquiring.InvoiceStatusReversed, "2025-08-12T10:00:00+03:00"),
+	} {
+		require.NoError(t, onEvent(ctx, event))
+	}
+
+	assert.Equal(t, []string{"1:processing", "1:success", "2:processing", "2:processing", "1:reversed"}, processed)
+
+	fail = true
+	event := testEvent(t, "3", monoacquiring.InvoiceStatusSuccess, "2025-08-11T06:08:52Z")
+
+	assert.ErrorIs(t, onEvent(ctx, event), failure)
+
+	fail = false
+
+	require.NoError(t, onEvent(ctx, event))
+	assert.Equal(t, "3:success", processed[len(processed)-1], "a failed event is processed on redelivery")
+}
+
+func TestDedupMiddleware_SameSecond(t *testing.T) {
+	var processed []string
+
+	store := NewMemoryEventStore(0)
+	onEvent := DedupMiddleware(store)(func(_ context.Context, event InvoiceEvent) error {
+		processed = append(processed, event.Status.String())
+
+		return nil
+	})
+
+	ctx := context.Background()
+
+	for _, event := range []InvoiceEvent{
+		testEvent(t, "1", monoacquiring.InvoiceStatusCreated, "2025-08-11T06:08:52Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusSuccess, "2025-08-11T06:08:52Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusProcessing, "2025-08-11T06:08:52Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusCreated, "2025-08-11T06:08:52Z"),
+		testEvent(t, "1", monoacquiring.InvoiceStatusReversed, "2025-08-11T06:08:52Z"),
+	} {
+		require.NoError(t, onEvent(ctx, event))
+	}
+
+	assert.Equal(t, []string{"created", "success", "reversed"}, processed,
+		"processing after success of the same second is dropped")
+
+	_, status, err := store.LastEvent(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, monoacquiring.InvoiceStatusReversed, status)
+}
+
+func TestMemoryEventStore_Evict(t *testing.T) {
+	store := NewMemoryEventStore(2)
+	ctx := context.Background()
+	modified := testEvent(t, "", "", "2025-08-11T06:08:52Z").ModifiedDate.Time
+
+	for _, invoiceID := range []string{"1", "2", "3"} {
+		require.NoError(t, store.Save(ctx, EventKey{InvoiceID: invoiceID}, modified))
+
+		if invoiceID == "2" {
+			seen, err := store.Seen(ctx, EventKey{InvoiceID: "1"})
+			require.NoError(t, err)
+			assert.True(t, seen, "marks 1 as recently used")
+		}
+	}
+
+	for invoiceID, expected := range map[string]bool{"1": true, "2": false, "3": true} {
+		last, _, err := store.LastEvent(ctx, invoiceID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, !last.IsZero(), invoiceID)
+	}
+}
+
+func TestHandler_Middlewares(t *testing.T) {
+	var calls []string
+
+	middleware := func(name string) EventMiddleware {
+		return func(next InvoiceEventFunc) InvoiceEventFunc {
+			return func(ctx context.Context, event InvoiceEvent) error {
+				calls = append(calls, name)
+
+				return next(ctx, event)
+			}
+		}
+	}
+
+	verifier, err := NewSignatureVerifier(testPublicKey)
+	require.NoError(t, err)
+
+	handler, err := NewHandler(HandlerConfig{
+		Verifier: verifier,
+		OnEvent: func(context.Context, InvoiceEvent) error {
+			calls = append(calls, "callback")
+
+			return nil
+		},
+		Middlewares: []EventMiddleware{
+			middleware("outer"),
+			DedupMiddleware(NewMemoryEventStore(0)),
+			middleware("inner"),
+		},
+	})
+	require.NoError(t, err)
+
+	for range 2 {
+		rec := serveWebhook(handler, http.MethodPost, testSign, testBody)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Equal(t, []string{"outer", "inner", "callback", "outer"}, calls, "the redelivery is dropped")
+}
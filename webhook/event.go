@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+)
+
+// InvoiceEvent is the body of a webhook, the invoice state as returned by GetInvoiceStatus plus payMethod.
+type InvoiceEvent struct {
+	Destination   *string                        `json:"destination,omitempty"`
+	TipsInfo      *monoacquiring.TipsInfo        `json:"tipsInfo,omitempty"`
+	FinalAmount   *int64                         `json:"finalAmount,omitempty"`
+	CreatedDate   monoacquiring.Timestamp        `json:"createdDate,omitempty"`
+	ModifiedDate  monoacquiring.Timestamp        `json:"modifiedDate,omitempty"`
+	Reference     *string                        `json:"reference,omitempty"`
+	ErrCode       *string                        `json:"errCode,omitempty"`
+	PaymentInfo   *monoacquiring.PaymentInfo     `json:"paymentInfo"`
+	FailureReason *string                        `json:"failureReason,omitempty"`
+	WalletData    *monoacquiring.WalletData      `json:"walletData,omitempty"`
+	InvoiceID     string                         `json:"invoiceId"`
+	Status        monoacquiring.InvoiceStatus    `json:"status"`
+	PayMethod     monoacquiring.PaymentMethod    `json:"payMethod,omitempty"`
+	CancelList    []monoacquiring.CancelListItem `json:"cancelList,omitempty"`
+	Amount        int64                          `json:"amount"`
+	Currency      monoacquiring.Currency         `json:"ccy"`
+}
+
+func (e InvoiceEvent) Money() monoacquiring.Money {
+	return monoacquiring.NewMoney(e.Amount, e.Currency)
+}
+
+// ToInvoiceStatus converts the event to the GetInvoiceStatus model, so events and polling share the downstream code.
+// All pointer fields and the cancel list are copied, changing the result does not change the event.
+func (e InvoiceEvent) ToInvoiceStatus() *monoacquiring.GetInvoiceStatusResponse {
+	return &monoacquiring.GetInvoiceStatusResponse{
+		Destination:   clonePointer(e.Destination),
+		TipsInfo:      clonePointer(e.TipsInfo),
+		FinalAmount:   clonePointer(e.FinalAmount),
+		CreatedDate:   e.CreatedDate,
+		ModifiedDate:  e.ModifiedDate,
+		Reference:     clonePointer(e.Reference),
+		ErrCode:       clonePointer(e.ErrCode),
+		PaymentInfo:   clonePointer(e.PaymentInfo),
+		FailureReason: clonePointer(e.FailureReason),
+		WalletData:    clonePointer(e.WalletData),
+		InvoiceID:     e.InvoiceID,
+		Status:        e.Status,
+		PayMethod:     e.PayMethod,
+		CancelList:    slices.Clone(e.CancelList),
+		Amount:        e.Amount,
+		Currency:      e.Currency,
+	}
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	return util.Pointer(*value)
+}
+
+// UnknownFieldsError is returned by DecodeInvoiceEventStrict for fields InvoiceEvent does not model.
+type UnknownFieldsError struct {
+	// Fields are dotted paths, e.g. "paymentInfo.extra" or "cancelList[0].extra".
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "unknown webhook fields: " + strings.Join(e.Fields, ", ")
+}
+
+// DecodeInvoiceEvent decodes the body leniently, the fields InvoiceEvent does not model are ignored and returned,
+// so they can be logged.
+func DecodeInvoiceEvent(body []byte) (InvoiceEvent, []string, error) {
+	var event InvoiceEvent
+
+	if err := json.Unmarshal(body, &event); err != nil {
+		return InvoiceEvent{}, nil, errors.WithStack(err)
+	}
+
+	var unknown []string
+
+	unknownFields(body, reflect.TypeFor[InvoiceEvent](), "", &unknown)
+	slices.Sort(unknown)
+
+	return event, unknown, nil
+}
+
+// DecodeInvoiceEventStrict decodes the body and fails with *UnknownFieldsError when it has unknown fields.
+func DecodeInvoiceEventStrict(body []byte) (InvoiceEvent, error) {
+	event, unknown, err := DecodeInvoiceEvent(body)
+	if err != nil {
+		return InvoiceEvent{}, err
+	}
+
+	if len(unknown) > 0 {
+		return InvoiceEvent{}, errors.WithStack(&UnknownFieldsError{Fields: unknown})
+	}
+
+	return event, nil
+}
+
+var jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+
+// unknownFields walks the JSON value along the type and collects the object keys the type has no field for.
+// Keys are matched case-insensitively, as encoding/json does.
+func unknownFields(data []byte, t reflect.Type, path string, unknown *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(jsonUnmarshaler) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+
+		if json.Unmarshal(data, &object) != nil {
+			return
+		}
+
+		fields := jsonFields(t)
+
+		for key, value := range object {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				*unknown = append(*unknown, path+key)
+
+				continue
+			}
+
+			unknownFields(value, field, path+key+".", unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		var array []json.RawMessage
+
+		if json.Unmarshal(data, &array) != nil {
+			return
+		}
+
+		prefix := strings.TrimSuffix(path, ".")
+
+		for i, value := range array {
+			unknownFields(value, t.Elem(), fmt.Sprintf("%s[%d].", prefix, i), unknown)
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of the struct keyed by the lower-cased JSON name.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+
+		fields[strings.ToLower(name)] = field.Type
+	}
+
+	return fields
+}
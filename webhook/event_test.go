@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"git.kbyte.app/mono/sdk/mono-acquiring-go/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInvoiceEvent(t *testing.T) {
+	event, unknown, err := DecodeInvoiceEvent([]byte(testBody))
+	require.NoError(t, err)
+	assert.Empty(t, unknown, "the test body is fully modelled")
+	assert.True(t, event.PayMethod.IsWallet())
+	assert.Equal(t, int64(20200), *event.FinalAmount)
+	assert.Equal(t, int64(263), event.PaymentInfo.Fee)
+	assert.Contains(t, *event.Destination, "Розрахунок")
+
+	event, err = DecodeInvoiceEventStrict([]byte(testBody))
+	require.NoError(t, err)
+	assert.Equal(t, "250811tUZjKAWjrnb9b", event.InvoiceID)
+}
+
+func TestDecodeInvoiceEvent_UnknownFields(t *testing.T) {
+	body := []byte(`{
+  "invoiceId": "1",
+  "Status": "success",
+  "extra": 1,
+  "paymentInfo": {"fee": 1, "rrnExt": "2"},
+  "cancelList": [{"amount": 1}, {"amount": 1, "reason": "3"}],
+  "modifiedDate": "2025-08-11T06:08:54Z"
+}`)
+
+	event, unknown, err := DecodeInvoiceEvent(body)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cancelList[1].reason", "extra", "paymentInfo.rrnExt"}, unknown)
+	assert.True(t, event.Status.IsSuccess(), "keys match case-insensitively")
+
+	_, err = DecodeInvoiceEventStrict(body)
+
+	var unknownErr *UnknownFieldsError
+
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, unknown, unknownErr.Fields)
+
+	_, _, err = DecodeInvoiceEvent([]byte(`{"amount": "1"}`))
+	assert.Error(t, err)
+}
+
+func TestInvoiceEvent_ToInvoiceStatus(t *testing.T) {
+	event, err := DecodeInvoiceEventStrict([]byte(testBody))
+	require.NoError(t, err)
+
+	status := event.ToInvoiceStatus()
+
+	assert.Equal(t, event.InvoiceID, status.InvoiceID)
+	assert.Equal(t, event.Status, status.Status)
+	assert.Equal(t, event.PayMethod, status.PayMethod)
+	assert.Equal(t, event.ModifiedDate, status.ModifiedDate)
+	assert.Equal(t, event.PaymentInfo, status.PaymentInfo)
+	assert.Equal(t, "202.00 UAH", status.Money().String())
+
+	final, ok := status.FinalMoney()
+	assert.True(t, ok)
+	assert.Equal(t, event.Money(), final)
+}
+
+func TestInvoiceEvent_ToInvoiceStatusCopy(t *testing.T) {
+	newEvent := func() InvoiceEvent {
+		return InvoiceEvent{
+			Destination:   util.Pointer("destination"),
+			TipsInfo:      &monoacquiring.TipsInfo{EmployeeID: "1", Amount: 100},
+			FinalAmount:   util.Pointer(int64(100)),
+			Reference:     util.Pointer("84d0070ee4e44667"),
+			ErrCode:       util.Pointer("59"),
+			PaymentInfo:   &monoacquiring.PaymentInfo{MaskedPan: "444403******1902"},
+			FailureReason: util.Pointer("invalid cvv"),
+			WalletData:    &monoacquiring.WalletData{CardToken: "67XZtXdR4NpKU3"},
+			CancelList:    []monoacquiring.CancelListItem{{Amount: 100}},
+		}
+	}
+
+	event := newEvent()
+
+	status := event.ToInvoiceStatus()
+	*status.Destination = "changed"
+	status.TipsInfo.Amount = 200
+	*status.FinalAmount = 200
+	*status.Reference = "changed"
+	*status.ErrCode = "changed"
+	status.PaymentInfo.MaskedPan = "changed"
+	*status.FailureReason = "changed"
+	status.WalletData.CardToken = "changed"
+	status.CancelList[0].Amount = 200
+
+	assert.Equal(t, newEvent(), event, "changing the result does not change the event")
+
+	assert.Nil(t, InvoiceEvent{}.ToInvoiceStatus().PaymentInfo)
+}
+
+func TestHandler_Strict(t *testing.T) {
+	key := newTestKey(t)
+	body := `{"invoiceId":"1","status":"success","extra":1}`
+
+	verifier, err := NewSignatureVerifier(key.public)
+	require.NoError(t, err)
+
+	onEvent := func(context.Context, InvoiceEvent) error { return nil }
+
+	for strict, expected := range map[bool]int{false: http.StatusOK, true: http.StatusBadRequest} {
+		handler, err := NewHandler(HandlerConfig{Verifier: verifier, OnEvent: onEvent, Strict: strict})
+		require.NoError(t, err)
+
+		rec := serveWebhook(handler, http.MethodPost, key.sign(t, body), body)
+		assert.Equal(t, expected, rec.Code)
+	}
+}
@@ -0,0 +1,128 @@
+//
+// https://monobank.ua/api-docs/acquiring/instrumenty-rozrobky/webhooks
+//
+
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SignatureHeader = "X-Sign"
+
+	DefaultMaxBodySize int64 = 1 << 20
+)
+
+// InvoiceEventFunc is called for every webhook that passed signature verification.
+// A returned error makes the handler reply with 500, so monobank delivers the event again.
+type InvoiceEventFunc func(ctx context.Context, event InvoiceEvent) error
+
+type HandlerConfig struct {
+	Verifier    *SignatureVerifier
+	OnEvent     InvoiceEventFunc
+	Middlewares []EventMiddleware
+	MaxBodySize int64
+	// Strict rejects a body with fields InvoiceEvent does not model with 400, see DecodeInvoiceEventStrict.
+	Strict bool
+}
+
+type Handler struct {
+	verifier    *SignatureVerifier
+	onEvent     InvoiceEventFunc
+	maxBodySize int64
+	strict      bool
+}
+
+func NewHandler(config HandlerConfig) (*Handler, error) {
+	if config.Verifier == nil {
+		return nil, errors.New("webhook handler requires a signature verifier")
+	}
+
+	if config.OnEvent == nil {
+		return nil, errors.New("webhook handler requires an event callback")
+	}
+
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultMaxBodySize
+	}
+
+	return &Handler{
+		verifier:    config.Verifier,
+		onEvent:     chainMiddlewares(config.OnEvent, config.Middlewares),
+		maxBodySize: config.MaxBodySize,
+		strict:      config.Strict,
+	}, nil
+}
+
+func (h *Handler) decode(body []byte) (InvoiceEvent, error) {
+	if h.strict {
+		return DecodeInvoiceEventStrict(body)
+	}
+
+	event, _, err := DecodeInvoiceEvent(body)
+
+	return event, err
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+
+		return
+	}
+
+	ok, err := h.verifier.VerifyContext(r.Context(), signature, body)
+
+	switch {
+	case err != nil && !errors.Is(err, ErrMalformedSignature):
+		// the public key is unavailable, monobank delivers the webhook again
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	case err != nil || !ok:
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	event, err := h.decode(body)
+	if err != nil || event.InvoiceID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err = h.onEvent(r.Context(), event); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
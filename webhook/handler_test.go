@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	private *ecdsa.PrivateKey
+	public  string
+}
+
+func newTestKey(t *testing.T) testKey {
+	t.Helper()
+
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	return testKey{private: private, public: base64.StdEncoding.EncodeToString(pemBytes)}
+}
+
+func (k testKey) sign(t *testing.T, body string) string {
+	t.Helper()
+
+	hash := sha256.Sum256([]byte(body))
+
+	sign, err := ecdsa.SignASN1(rand.Reader, k.private, hash[:])
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(sign)
+}
+
+func newTestHandler(t *testing.T, publicKey string, onEvent InvoiceEventFunc, maxBodySize int64) *Handler {
+	t.Helper()
+
+	verifier, err := NewSignatureVerifier(publicKey)
+	require.NoError(t, err)
+
+	handler, err := NewHandler(HandlerConfig{Verifier: verifier, OnEvent: onEvent, MaxBodySize: maxBodySize})
+	require.NoError(t, err)
+
+	return handler
+}
+
+func serveWebhook(handler http.Handler, method, signature, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	var received []InvoiceEvent
+
+	handler := newTestHandler(t, testPublicKey, func(_ context.Context, event InvoiceEvent) error {
+		received = append(received, event)
+
+		return nil
+	}, 0)
+
+	rec := serveWebhook(handler, http.MethodPost, testSign, testBody)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, received, 1)
+
+	event := received[0]
+
+	assert.Equal(t, "250811tUZjKAWjrnb9b", event.InvoiceID)
+	assert.True(t, event.Status.IsSuccess())
+	assert.Equal(t, int64(20200), event.Amount)
+	assert.Equal(t, "202.00 UAH", event.Money().String())
+	assert.Equal(t, "ce223cb7-1c95-4f3b-8a3e-2a5fe21bce6c", *event.Reference)
+	assert.NotNil(t, event.PaymentInfo)
+	assert.Equal(t, int64(263), event.PaymentInfo.Fee)
+	assert.True(t, event.PaymentInfo.PaymentSystem.IsVisa())
+}
+
+func TestHandler_Rejected(t *testing.T) {
+	key := newTestKey(t)
+
+	tests := map[string]struct {
+		Method      string
+		PublicKey   string
+		Signature   string
+		Body        string
+		MaxBodySize int64
+		StatusCode  int
+	}{
+		"wrong method": {
+			Method:     http.MethodGet,
+			Signature:  testSign,
+			Body:       testBody,
+			StatusCode: http.StatusMethodNotAllowed,
+		},
+		"missing signature": {
+			Method:     http.MethodPost,
+			Body:       testBody,
+			StatusCode: http.StatusUnauthorized,
+		},
+		"invalid signature": {
+			Method:     http.MethodPost,
+			Signature:  testSign,
+			Body:       `{}`,
+			StatusCode: http.StatusUnauthorized,
+		},
+		"non base64 signature": {
+			Method:     http.MethodPost,
+			Signature:  "not base64!",
+			Body:       testBody,
+			StatusCode: http.StatusUnauthorized,
+		},
+		"body too large": {
+			Method:      http.MethodPost,
+			Signature:   testSign,
+			Body:        testBody,
+			MaxBodySize: 16,
+			StatusCode:  http.StatusRequestEntityTooLarge,
+		},
+		"malformed json": {
+			Method:     http.MethodPost,
+			PublicKey:  key.public,
+			Signature:  key.sign(t, `test`),
+			Body:       `test`,
+			StatusCode: http.StatusBadRequest,
+		},
+		"missing invoice id": {
+			Method:     http.MethodPost,
+			PublicKey:  key.public,
+			Signature:  key.sign(t, `{"status":"success"}`),
+			Body:       `{"status":"success"}`,
+			StatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			publicKey := testPublicKey
+			if val.PublicKey != "" {
+				publicKey = val.PublicKey
+			}
+
+			called := false
+			handler := newTestHandler(t, publicKey, func(context.Context, InvoiceEvent) error {
+				called = true
+
+				return nil
+			}, val.MaxBodySize)
+
+			rec := serveWebhook(handler, val.Method, val.Signature, val.Body)
+
+			assert.Equal(t, val.StatusCode, rec.Code)
+			assert.False(t, called)
+		})
+	}
+}
+
+func TestHandler_CallbackError(t *testing.T) {
+	handler := newTestHandler(t, testPublicKey, func(context.Context, InvoiceEvent) error {
+		return errors.New("storage is down")
+	}, 0)
+
+	rec := serveWebhook(handler, http.MethodPost, testSign, testBody)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestHandler_KeyUnavailable(t *testing.T) {
+	getter := &fakePublicKeyGetter{err: errors.New("api is down")}
+
+	called := false
+
+	handler, err := NewHandler(HandlerConfig{
+		Verifier: NewSignatureVerifierWithProvider(NewClientKeyProvider(getter, 0)),
+		OnEvent: func(context.Context, InvoiceEvent) error {
+			called = true
+
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	rec := serveWebhook(handler, http.MethodPost, testSign, testBody)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "the failed fetch is retried by monobank")
+
+	rec = serveWebhook(handler, http.MethodPost, testSign, testBody)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "during the failure backoff")
+
+	rec = serveWebhook(handler, http.MethodPost, "not base64!", testBody)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "a malformed signature is rejected before the key")
+
+	assert.False(t, called)
+}
+
+func TestNewHandler_Error(t *testing.T) {
+	verifier, err := NewSignatureVerifier(testPublicKey)
+	require.NoError(t, err)
+
+	onEvent := func(context.Context, InvoiceEvent) error { return nil }
+
+	tests := map[string]HandlerConfig{
+		"no verifier": {OnEvent: onEvent},
+		"no callback": {Verifier: verifier},
+	}
+
+	for name, val := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler, err := NewHandler(val)
+
+			assert.Error(t, err)
+			assert.Nil(t, handler)
+		})
+	}
+}
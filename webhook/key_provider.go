@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+)
+
+const DefaultRefreshCooldown = time.Minute
+
+var ErrPublicKeyUnavailable = errors.New("public key is not available")
+
+type KeyProvider interface {
+	PublicKey(ctx context.Context) (*ecdsa.PublicKey, error)
+}
+
+// KeyRefresher is implemented by providers that can replace a key after a signature did not match it.
+// Refresh receives the key that failed and returns the key to retry with.
+type KeyRefresher interface {
+	Refresh(ctx context.Context, stale *ecdsa.PublicKey) (*ecdsa.PublicKey, error)
+}
+
+// PublicKeyGetter is satisfied by *monoacquiring.Client.
+type PublicKeyGetter interface {
+	GetPublicKey(ctx context.Context) (*monoacquiring.GetPublicKeyResponse, error)
+}
+
+type staticKeyProvider struct {
+	key *ecdsa.PublicKey
+}
+
+func (p staticKeyProvider) PublicKey(context.Context) (*ecdsa.PublicKey, error) {
+	return p.key, nil
+}
+
+// failureBackoff is how long a failed fetch is not retried, much shorter than the cooldown so an outage of the
+// API does not reject the webhooks for the whole cooldown.
+const failureBackoff = time.Second
+
+// ClientKeyProvider caches the key returned by GetPublicKey and fetches it again when monobank rotates it.
+// Verifications only read the cached key, so they never wait for a fetch in progress.
+// Fetches happen at most once per cooldown after a successful one, so forged signatures cannot be used to flood
+// the API. Concurrent callers share a single fetch, which runs without holding the lock.
+type ClientKeyProvider struct {
+	fetchedAt time.Time
+	failedAt  time.Time
+	client    PublicKeyGetter
+	now       func() time.Time
+	key       atomic.Pointer[ecdsa.PublicKey]
+	fetching  *keyFetch
+	cooldown  time.Duration
+	mu        sync.Mutex
+}
+
+// keyFetch is a fetch in progress, done is closed once key or err is set.
+type keyFetch struct {
+	err  error
+	key  *ecdsa.PublicKey
+	done chan struct{}
+}
+
+func NewClientKeyProvider(client PublicKeyGetter, cooldown time.Duration) *ClientKeyProvider {
+	if cooldown <= 0 {
+		cooldown = DefaultRefreshCooldown
+	}
+
+	return &ClientKeyProvider{client: client, cooldown: cooldown, now: time.Now}
+}
+
+func (p *ClientKeyProvider) PublicKey(ctx context.Context) (*ecdsa.PublicKey, error) {
+	if key := p.key.Load(); key != nil {
+		return key, nil
+	}
+
+	p.mu.Lock()
+
+	if key := p.key.Load(); key != nil {
+		p.mu.Unlock()
+
+		return key, nil
+	}
+
+	if p.backingOff() {
+		p.mu.Unlock()
+
+		return nil, ErrPublicKeyUnavailable
+	}
+
+	return p.fetch(ctx)
+}
+
+func (p *ClientKeyProvider) Refresh(ctx context.Context, stale *ecdsa.PublicKey) (*ecdsa.PublicKey, error) {
+	p.mu.Lock()
+
+	current := p.key.Load()
+
+	if (current != nil && !current.Equal(stale)) || p.coolingDown() || p.backingOff() {
+		p.mu.Unlock()
+
+		return current, nil
+	}
+
+	return p.fetch(ctx)
+}
+
+func (p *ClientKeyProvider) coolingDown() bool {
+	return !p.fetchedAt.IsZero() && p.now().Sub(p.fetchedAt) < p.cooldown
+}
+
+func (p *ClientKeyProvider) backingOff() bool {
+	return !p.failedAt.IsZero() && p.now().Sub(p.failedAt) < failureBackoff
+}
+
+// fetch is called with the lock held and releases it. It joins the fetch in progress or starts one.
+func (p *ClientKeyProvider) fetch(ctx context.Context) (*ecdsa.PublicKey, error) {
+	f := p.fetching
+	if f == nil {
+		f = &keyFetch{done: make(chan struct{})}
+		p.fetching = f
+		p.mu.Unlock()
+
+		p.run(ctx, f)
+	} else {
+		p.mu.Unlock()
+	}
+
+	select {
+	case <-f.done:
+		return f.key, f.err
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	}
+}
+
+func (p *ClientKeyProvider) run(ctx context.Context, f *keyFetch) {
+	res, err := p.client.GetPublicKey(ctx)
+	if err != nil {
+		f.err = errors.Wrap(err, "failed to fetch public key")
+	} else {
+		f.key, f.err = parsePublicKey(res.Key)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if f.err != nil {
+		p.failedAt = p.now()
+	} else {
+		p.fetchedAt = p.now()
+		p.failedAt = time.Time{}
+		p.key.Store(f.key)
+	}
+
+	p.fetching = nil
+	close(f.done)
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	monoacquiring "git.kbyte.app/mono/sdk/mono-acquiring-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublicKeyGetter struct {
+	err   error
+	key   atomic.Value
+	calls atomic.Int32
+}
+
+func (g *fakePublicKeyGetter) GetPublicKey(context.Context) (*monoacquiring.GetPublicKeyResponse, error) {
+	g.calls.Add(1)
+
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	return &monoacquiring.GetPublicKeyResponse{Key: g.key.Load().(string)}, nil
+}
+
+func TestClientKeyProvider_Rotation(t *testing.T) {
+	oldKey := newTestKey(t)
+	newKey := newTestKey(t)
+
+	getter := &fakePublicKeyGetter{}
+	getter.key.Store(oldKey.public)
+
+	now := time.Now()
+	provider := NewClientKeyProvider(getter, time.Minute)
+	provider.now = func() time.Time { return now }
+
+	verifier := NewSignatureVerifierWithProvider(provider)
+	ctx := context.Background()
+
+	ok, err := verifier.VerifyContext(ctx, oldKey.sign(t, "body"), []byte("body"))
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), getter.calls.Load())
+
+	getter.key.Store(newKey.public)
+
+	// still within the cooldown, so the rotated key is not fetched yet
+	ok, err = verifier.VerifyContext(ctx, newKey.sign(t, "body"), []byte("body"))
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, int32(1), getter.calls.Load())
+
+	now = now.Add(2 * time.Minute)
+
+	ok, err = verifier.VerifyContext(ctx, newKey.sign(t, "body"), []byte("body"))
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), getter.calls.Load())
+
+	// a forged signature right after the rotation does not cause another fetch
+	ok, err = verifier.VerifyContext(ctx, oldKey.sign(t, "body"), []byte("body"))
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, int32(2), getter.calls.Load())
+}
+
+func TestClientKeyProvider_Concurrent(t *testing.T) {
+	key := newTestKey(t)
+
+	getter := &fakePublicKeyGetter{}
+	getter.key.Store(key.public)
+
+	verifier := NewSignatureVerifierWithProvider(NewClientKeyProvider(getter, 0))
+	sign := key.sign(t, "body")
+
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ok, err := verifier.Verify(sign, []byte("body"))
+
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), getter.calls.Load())
+}
+
+func TestClientKeyProvider_Error(t *testing.T) {
+	key := newTestKey(t)
+	getter := &fakePublicKeyGetter{err: errors.New("connection refused")}
+
+	now := time.Now()
+	provider := NewClientKeyProvider(getter, time.Minute)
+	provider.now = func() time.Time { return now }
+
+	_, err := provider.PublicKey(context.Background())
+	assert.Error(t, err)
+
+	_, err = provider.PublicKey(context.Background())
+	assert.ErrorIs(t, err, ErrPublicKeyUnavailable, "a failed fetch is not retried at once")
+	assert.Equal(t, int32(1), getter.calls.Load())
+
+	getter.key.Store("not a key")
+	getter.err = nil
+	now = now.Add(failureBackoff)
+
+	_, err = provider.PublicKey(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), getter.calls.Load(), "a failure does not start the cooldown")
+
+	getter.key.Store(key.public)
+	now = now.Add(failureBackoff)
+
+	actual, err := provider.PublicKey(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, actual)
+	assert.Equal(t, int32(3), getter.calls.Load())
+}
+
+type blockingPublicKeyGetter struct {
+	release chan struct{}
+	started chan struct{}
+	key     string
+}
+
+func (g *blockingPublicKeyGetter) GetPublicKey(context.Context) (*monoacquiring.GetPublicKeyResponse, error) {
+	close(g.started)
+	<-g.release
+
+	return &monoacquiring.GetPublicKeyResponse{Key: g.key}, nil
+}
+
+func TestClientKeyProvider_FetchWithoutLock(t *testing.T) {
+	key := newTestKey(t)
+	getter := &blockingPublicKeyGetter{key: key.public, started: make(chan struct{}), release: make(chan struct{})}
+	provider := NewClientKeyProvider(getter, 0)
+
+	fetched := make(chan error)
+
+	go func() {
+		_, err := provider.PublicKey(context.Background())
+		fetched <- err
+	}()
+
+	<-getter.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := provider.PublicKey(ctx)
+	assert.ErrorIs(t, err, context.Canceled, "a waiter is not blocked behind the fetch in progress")
+
+	close(getter.release)
+	require.NoError(t, <-fetched)
+
+	actual, err := provider.PublicKey(context.Background())
+	require.NoError(t, err)
+	assert.True(t, actual.Equal(provider.key.Load()))
+}
+
+func TestClientKeyProvider_Client(t *testing.T) {
+	key := newTestKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/merchant/pubkey", func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"key": "`+key.public+`"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := monoacquiring.NewClient(monoacquiring.Config{APIKey: "test", BaseURL: srv.URL}, srv.Client(), nil)
+	require.NoError(t, err)
+
+	verifier := NewSignatureVerifierWithProvider(NewClientKeyProvider(client, 0))
+
+	ok, err := verifier.Verify(key.sign(t, "body"), []byte("body"))
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
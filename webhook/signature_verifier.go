@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/sha256"
 	"crypto/x509"
@@ -10,11 +11,69 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrMalformedSignature is returned by VerifyContext for a signature that is not base64, its other errors come
+// from the key provider.
+var ErrMalformedSignature = errors.New("signature is not base64")
+
 type SignatureVerifier struct {
-	pubKey *ecdsa.PublicKey
+	provider KeyProvider
 }
 
 func NewSignatureVerifier(pubKey string) (*SignatureVerifier, error) {
+	key, err := parsePublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignatureVerifier{provider: staticKeyProvider{key: key}}, nil
+}
+
+// NewSignatureVerifierWithProvider creates a verifier that takes the public key from the provider.
+// When the provider also implements KeyRefresher, a signature that does not match the current key
+// triggers a refresh and is checked once more against the new key.
+func NewSignatureVerifierWithProvider(provider KeyProvider) *SignatureVerifier {
+	return &SignatureVerifier{provider: provider}
+}
+
+func (sv *SignatureVerifier) Verify(signature string, body []byte) (bool, error) {
+	return sv.VerifyContext(context.Background(), signature, body)
+}
+
+func (sv *SignatureVerifier) VerifyContext(ctx context.Context, signature string, body []byte) (bool, error) {
+	sign, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, errors.Wrap(ErrMalformedSignature, err.Error())
+	}
+
+	key, err := sv.provider.PublicKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha256.Sum256(body)
+
+	if ecdsa.VerifyASN1(key, hash[:], sign) {
+		return true, nil
+	}
+
+	refresher, ok := sv.provider.(KeyRefresher)
+	if !ok {
+		return false, nil
+	}
+
+	fresh, err := refresher.Refresh(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	if fresh == nil || fresh.Equal(key) {
+		return false, nil
+	}
+
+	return ecdsa.VerifyASN1(fresh, hash[:], sign), nil
+}
+
+func parsePublicKey(pubKey string) (*ecdsa.PublicKey, error) {
 	pubKeyBytes, err := base64.StdEncoding.DecodeString(pubKey)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -30,25 +89,10 @@ func NewSignatureVerifier(pubKey string) (*SignatureVerifier, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	var (
-		ok bool
-		sv = SignatureVerifier{}
-	)
-
-	if sv.pubKey, ok = genericPubKey.(*ecdsa.PublicKey); !ok {
+	key, ok := genericPubKey.(*ecdsa.PublicKey)
+	if !ok {
 		return nil, errors.New("failed to assert type of public key")
 	}
 
-	return &sv, nil
-}
-
-func (sv *SignatureVerifier) Verify(signature string, body []byte) (bool, error) {
-	sign, err := base64.StdEncoding.DecodeString(signature)
-	if err != nil {
-		return false, errors.WithStack(err)
-	}
-
-	hash := sha256.Sum256(body)
-
-	return ecdsa.VerifyASN1(sv.pubKey, hash[:], sign), nil
+	return key, nil
 }